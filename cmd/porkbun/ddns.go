@@ -0,0 +1,363 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+// ddnsBaseRetryDelay is how long ddns waits before trying again after a
+// failed update. It doubles with each failure in a row, up to the interval.
+const ddnsBaseRetryDelay = 30 * time.Second
+
+// ddnsRecord is a record ddns keeps pointed at the public address of this
+// host, written as DOMAIN/NAME/TYPE.
+type ddnsRecord struct {
+	domain, name, recordType string
+}
+
+func (r ddnsRecord) String() string {
+	name := r.name
+	if name == "" {
+		name = "@"
+	}
+	return r.domain + "/" + name + "/" + r.recordType
+}
+
+// parseDDNSRecord parses a record written as DOMAIN/NAME/TYPE, where NAME is
+// empty or @ for the domain itself and TYPE is A or AAAA.
+func parseDDNSRecord(s string) (ddnsRecord, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 3 || parts[0] == "" {
+		return ddnsRecord{}, fmt.Errorf("invalid record %q; expected DOMAIN/NAME/TYPE, such as example.com/home/A", s)
+	}
+
+	domain := normalizeDomain(parts[0])
+	record := ddnsRecord{
+		domain:     domain,
+		name:       porkbun.Subdomain(strings.ToLower(strings.TrimPrefix(parts[1], "@")), domain),
+		recordType: strings.ToUpper(parts[2]),
+	}
+	if record.recordType != "A" && record.recordType != "AAAA" {
+		return ddnsRecord{}, fmt.Errorf("invalid record %q; only A and AAAA records can be kept up to date", s)
+	}
+	return record, nil
+}
+
+// discoverer returns the public address of this host to use for records of
+// recordType, A or AAAA.
+type discoverer func(ctx context.Context, recordType string) (netip.Addr, error)
+
+// ddnsState is what ddns keeps in its state file across restarts.
+type ddnsState struct {
+	// Addresses maps each record to the address ddns last saw in it.
+	Addresses map[string]string `json:"addresses"`
+}
+
+// ddns keeps records pointed at the public address of this host.
+type ddns struct {
+	client    *porkbun.Client
+	records   []ddnsRecord
+	discover  discoverer
+	statePath string
+	state     ddnsState
+	logger    *slog.Logger
+}
+
+// loadState reads the state file, if there is one.
+func (d *ddns) loadState() error {
+	d.state = ddnsState{Addresses: make(map[string]string)}
+
+	data, err := os.ReadFile(d.statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, &d.state); err != nil {
+		return fmt.Errorf("invalid state file %s: %w", d.statePath, err)
+	}
+	if d.state.Addresses == nil {
+		d.state.Addresses = make(map[string]string)
+	}
+	return nil
+}
+
+// saveState writes the state file, replacing it whole so that it is never
+// left half written.
+func (d *ddns) saveState() error {
+	data, err := json.MarshalIndent(d.state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(d.statePath), 0o700); err != nil {
+		return err
+	}
+	tmp := d.statePath + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, d.statePath)
+}
+
+// update points each record at the current public address. The API is only
+// called for records whose address has changed since it was last seen, so
+// that an unchanged address costs nothing but the discovery.
+func (d *ddns) update(ctx context.Context) error {
+	addrs := make(map[string]netip.Addr)
+	var errs []error
+
+	for _, record := range d.records {
+		addr, ok := addrs[record.recordType]
+		if !ok {
+			var err error
+			if addr, err = d.discover(ctx, record.recordType); err != nil {
+				errs = append(errs, fmt.Errorf("failed to discover the public address for %s: %w", record, err))
+				continue
+			}
+			addrs[record.recordType] = addr
+		}
+
+		key := record.String()
+		if d.state.Addresses[key] == addr.String() {
+			continue
+		}
+
+		if err := d.updateRecord(ctx, record, addr); err != nil {
+			errs = append(errs, fmt.Errorf("failed to update %s: %w", record, err))
+			continue
+		}
+
+		d.state.Addresses[key] = addr.String()
+		if err := d.saveState(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to save state: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// updateRecord points record at addr, keeping its TTL, priority and notes,
+// unless it already points there.
+func (d *ddns) updateRecord(ctx context.Context, record ddnsRecord, addr netip.Addr) error {
+	records, err := d.client.RetrieveDNSRecordsByNameType(ctx, record.domain, record.recordType, record.name)
+	if err != nil {
+		return err
+	}
+	if len(records) != 1 {
+		return fmt.Errorf("expected one record, found %d", len(records))
+	}
+
+	current := records[0]
+	if current.Content == addr.String() {
+		d.logger.InfoContext(ctx, "Record is up to date", "record", record.String(), "address", current.Content)
+		return nil
+	}
+
+	err = d.client.EditDNSRecord(ctx, record.domain, current.ID, porkbun.EditDNSRecordRequest{
+		Name:    record.name,
+		Type:    record.recordType,
+		Content: addr.String(),
+		TTL:     current.TTL,
+		Prio:    current.Prio,
+		Notes:   current.Notes,
+	})
+	if err != nil {
+		return err
+	}
+
+	d.logger.InfoContext(ctx, "Updated record", "record", record.String(), "old_address", current.Content, "address", addr.String())
+	return nil
+}
+
+// ddnsDelay returns how long to wait before the next update: the interval,
+// or after failures a delay doubling from ddnsBaseRetryDelay up to the
+// interval, plus a random jitter of up to jitter, so that many hosts
+// started together do not call the API together.
+func ddnsDelay(interval, jitter time.Duration, failures int) time.Duration {
+	delay := interval
+	if failures > 0 {
+		delay = ddnsBaseRetryDelay
+		for i := 1; i < failures && delay < interval; i++ {
+			delay *= 2
+		}
+		delay = min(delay, interval)
+	}
+
+	if jitter > 0 {
+		delay += rand.N(jitter)
+	}
+	return delay
+}
+
+// ddnsStartDelay returns how long to wait before the first update: a random
+// delay of up to jitter, so that many hosts started together, such as after
+// a power cut, do not call the API together. With once there is no delay.
+func ddnsStartDelay(jitter time.Duration, once bool) time.Duration {
+	if once || jitter <= 0 {
+		return 0
+	}
+	return rand.N(jitter)
+}
+
+func runDDNS(ctx context.Context, c *cli, fs *flag.FlagSet, args []string) error {
+	defaultStatePath := "porkbun-ddns.json"
+	if dir, err := os.UserCacheDir(); err == nil {
+		defaultStatePath = filepath.Join(dir, "porkbun", "ddns.json")
+	}
+
+	interval := fs.Duration("interval", 5*time.Minute, "how often to check the public address")
+	jitter := fs.Duration("jitter", 30*time.Second, "largest random delay before the first update and added to each interval")
+	iface := fs.String("interface", "", "take the address from this network interface instead of asking the API")
+	statePath := fs.String("state", defaultStatePath, "file to keep the last seen addresses in across restarts")
+	once := fs.Bool("once", false, "update the records once and exit")
+	args, err := c.parse(fs, args, 1, -1)
+	if err != nil {
+		return err
+	}
+
+	d := &ddns{statePath: *statePath, logger: c.logger()}
+	for _, arg := range args {
+		record, err := parseDDNSRecord(arg)
+		if err != nil {
+			return err
+		}
+		d.records = append(d.records, record)
+	}
+
+	if d.client, err = c.client(); err != nil {
+		return err
+	}
+	if *iface != "" {
+		d.discover = interfaceDiscoverer(*iface)
+	} else if d.discover, err = c.pingDiscoverer(); err != nil {
+		return err
+	}
+	if err := d.loadState(); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-time.After(ddnsStartDelay(*jitter, *once)):
+	}
+
+	for failures := 0; ; {
+		err := d.update(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if *once {
+			return err
+		}
+
+		if err != nil {
+			failures++
+			d.logger.ErrorContext(ctx, "Update failed", "error", err)
+		} else {
+			failures = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(ddnsDelay(*interval, *jitter, failures)):
+		}
+	}
+}
+
+// pingDiscoverer returns a discoverer that asks the ping endpoint which
+// address it sees, connecting over IPv4 for A records and over IPv6 for
+// AAAA records.
+func (c *cli) pingDiscoverer() (discoverer, error) {
+	clients := make(map[string]*porkbun.Client)
+	for recordType, network := range map[string]string{"A": "tcp4", "AAAA": "tcp6"} {
+		dialer := &net.Dialer{Timeout: 30 * time.Second}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.DialContext = func(ctx context.Context, _, address string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, address)
+		}
+
+		client, err := c.client(porkbun.WithHTTPClient(&http.Client{Timeout: 30 * time.Second, Transport: transport}))
+		if err != nil {
+			return nil, err
+		}
+		clients[recordType] = client
+	}
+
+	return func(ctx context.Context, recordType string) (netip.Addr, error) {
+		ip, err := clients[recordType].Ping(ctx)
+		if err != nil {
+			return netip.Addr{}, err
+		}
+
+		addr, err := netip.ParseAddr(ip)
+		if err != nil {
+			return netip.Addr{}, fmt.Errorf("invalid address %q from the API", ip)
+		}
+		addr = addr.Unmap()
+		if addr.Is4() != (recordType == "A") {
+			return netip.Addr{}, fmt.Errorf("the API saw address %s, which cannot be used for %s records", addr, recordType)
+		}
+		return addr, nil
+	}, nil
+}
+
+// interfaceDiscoverer returns a discoverer that takes the first public
+// address of the network interface named name.
+func interfaceDiscoverer(name string) discoverer {
+	return func(ctx context.Context, recordType string) (netip.Addr, error) {
+		iface, err := net.InterfaceByName(name)
+		if err != nil {
+			return netip.Addr{}, err
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return netip.Addr{}, err
+		}
+
+		if addr, ok := publicAddress(addrs, recordType); ok {
+			return addr, nil
+		}
+		return netip.Addr{}, fmt.Errorf("interface %s has no public address for %s records", name, recordType)
+	}
+}
+
+// publicAddress returns the first of addrs that is a public IPv4 address
+// for A records, or a public IPv6 address for AAAA records.
+func publicAddress(addrs []net.Addr, recordType string) (netip.Addr, bool) {
+	for _, a := range addrs {
+		ipnet, ok := a.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		addr, ok := netip.AddrFromSlice(ipnet.IP)
+		if !ok {
+			continue
+		}
+
+		addr = addr.Unmap()
+		if addr.Is4() != (recordType == "A") || !addr.IsGlobalUnicast() || addr.IsPrivate() {
+			continue
+		}
+		return addr, true
+	}
+	return netip.Addr{}, false
+}
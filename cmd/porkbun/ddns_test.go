@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neenaoffline/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+func TestParseDDNSRecord(t *testing.T) {
+	tests := map[string]string{
+		"example.com/home/A":              "example.com/home/A",
+		"Example.com./@/aaaa":             "example.com/@/AAAA",
+		"example.com//A":                  "example.com/@/A",
+		"example.com/home.example.com./A": "example.com/home/A",
+	}
+	for input, want := range tests {
+		record, err := parseDDNSRecord(input)
+		if err != nil {
+			t.Errorf("parseDDNSRecord(%q): unexpected error: %s", input, err)
+			continue
+		}
+		if record.String() != want {
+			t.Errorf("parseDDNSRecord(%q) = %s, want %s", input, record, want)
+		}
+	}
+
+	for _, input := range []string{"example.com/home", "example.com/home/CNAME", "/home/A"} {
+		if _, err := parseDDNSRecord(input); err == nil {
+			t.Errorf("parseDDNSRecord(%q): expected an error", input)
+		}
+	}
+}
+
+func TestDDNS_Update(t *testing.T) {
+	api := &porkbuntest.API{Records: []porkbun.DNSRecord{
+		{ID: "1", Name: "home.example.com", Type: "A", Content: "192.0.2.1", TTL: "600", Notes: "home router"},
+		{ID: "2", Name: "example.com", Type: "AAAA", Content: "2001:db8::1", TTL: "600"},
+	}}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	addrs := map[string]netip.Addr{
+		"A":    netip.MustParseAddr("198.51.100.7"),
+		"AAAA": netip.MustParseAddr("2001:db8::1"),
+	}
+	statePath := filepath.Join(t.TempDir(), "state", "ddns.json")
+	newDDNS := func() *ddns {
+		d := &ddns{
+			client:    porkbun.NewClient("pk1_test", "sk1_test", porkbun.WithBaseURL(server.URL)),
+			records:   []ddnsRecord{{"example.com", "home", "A"}, {"example.com", "", "AAAA"}},
+			statePath: statePath,
+			logger:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+			discover: func(ctx context.Context, recordType string) (netip.Addr, error) {
+				return addrs[recordType], nil
+			},
+		}
+		if err := d.loadState(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		return d
+	}
+
+	d := newDDNS()
+	if err := d.update(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if record := api.Records[0]; record.Content != "198.51.100.7" || record.Notes != "home router" || record.Name != "home.example.com" {
+		t.Fatalf("expected the A record to be updated and keep its notes, got %+v", record)
+	}
+	if api.Records[1].Content != "2001:db8::1" {
+		t.Fatalf("expected the AAAA record to be left alone, got %+v", api.Records[1])
+	}
+
+	// An unchanged address does not call the API, even after a restart
+	restarted := newDDNS()
+	requests := api.Requests
+	if err := restarted.update(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if api.Requests != requests {
+		t.Fatalf("expected no API requests for unchanged addresses, got %d", api.Requests-requests)
+	}
+
+	addrs["A"] = netip.MustParseAddr("198.51.100.8")
+	if err := restarted.update(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if api.Records[0].Content != "198.51.100.8" {
+		t.Fatalf("expected the A record to follow the new address, got %+v", api.Records[0])
+	}
+
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(string(data), `"example.com/home/A": "198.51.100.8"`) {
+		t.Fatalf("expected the new address in the state file, got %s", data)
+	}
+
+	// A missing record is an error, and is tried again next time
+	d.records = append(d.records, ddnsRecord{"example.com", "office", "A"})
+	if err := d.update(context.Background()); err == nil || !strings.Contains(err.Error(), "example.com/office/A") {
+		t.Fatalf("expected an error naming the missing record, got %v", err)
+	}
+	if _, ok := d.state.Addresses["example.com/office/A"]; ok {
+		t.Fatal("expected no state for the missing record")
+	}
+}
+
+func TestDDNSDelay(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 5 * time.Minute},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{4, 4 * time.Minute},
+		{5, 5 * time.Minute},
+		{20, 5 * time.Minute},
+	}
+	for _, test := range tests {
+		if got := ddnsDelay(5*time.Minute, 0, test.failures); got != test.want {
+			t.Errorf("ddnsDelay after %d failures = %s, want %s", test.failures, got, test.want)
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		if got := ddnsDelay(5*time.Minute, 30*time.Second, 0); got < 5*time.Minute || got >= 5*time.Minute+30*time.Second {
+			t.Fatalf("expected a jitter of under 30s, got %s", got)
+		}
+	}
+}
+
+func TestDDNSStartDelay(t *testing.T) {
+	if got := ddnsStartDelay(30*time.Second, true); got != 0 {
+		t.Fatalf("expected no delay with -once, got %s", got)
+	}
+	if got := ddnsStartDelay(0, false); got != 0 {
+		t.Fatalf("expected no delay without jitter, got %s", got)
+	}
+
+	for i := 0; i < 100; i++ {
+		if got := ddnsStartDelay(30*time.Second, false); got < 0 || got >= 30*time.Second {
+			t.Fatalf("expected a delay of under 30s, got %s", got)
+		}
+	}
+}
+
+func TestPublicAddress(t *testing.T) {
+	var addrs []net.Addr
+	for _, cidr := range []string{"127.0.0.1/8", "192.168.1.10/24", "fe80::1/64", "fd00::1/64", "203.0.113.5/24", "2001:db8::5/64"} {
+		ip, ipnet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ipnet.IP = ip
+		addrs = append(addrs, ipnet)
+	}
+
+	if addr, ok := publicAddress(addrs, "A"); !ok || addr.String() != "203.0.113.5" {
+		t.Errorf("expected the public IPv4 address, got %s", addr)
+	}
+	if addr, ok := publicAddress(addrs, "AAAA"); !ok || addr.String() != "2001:db8::5" {
+		t.Errorf("expected the public IPv6 address, got %s", addr)
+	}
+	if _, ok := publicAddress(addrs[:2], "A"); ok {
+		t.Error("expected no public address among loopback and private addresses")
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/neenaoffline/terraform-provider-porkbun/internal/zonefile"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func runGenerate(ctx context.Context, c *cli, fs *flag.FlagSet, args []string) error {
+	withNameServers := fs.Bool("nameservers", true, "include a porkbun_domain_nameservers resource for the domain's name servers")
+	args, err := c.parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	domain := normalizeDomain(args[0])
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	records, err := client.RetrieveDNSRecords(ctx, domain)
+	if err != nil {
+		return err
+	}
+
+	var nameservers []string
+	if *withNameServers {
+		if nameservers, err = client.GetNameServers(ctx, domain); err != nil {
+			return err
+		}
+	}
+
+	_, err = io.WriteString(c.stdout, generateConfig(domain, records, nameservers))
+	return err
+}
+
+// generateConfig returns Terraform configuration that brings the records
+// and name servers of domain under management: a porkbun_dns_record
+// resource for each record, a porkbun_domain_nameservers resource if
+// nameservers is not empty, and an import block for each of them.
+//
+// Resource names are derived from each record's name, type and a hash of
+// its content, so that generating the configuration again gives every
+// record the same address. The domain's own NS records are left out, as
+// they are managed with porkbun_domain_nameservers.
+func generateConfig(domain string, records []porkbun.DNSRecord, nameservers []string) string {
+	sorted := make([]porkbun.DNSRecord, 0, len(records))
+	for _, record := range records {
+		record.Name = normalizeDomain(record.Name)
+		if record.Type == "NS" && record.Name == domain {
+			continue
+		}
+		sorted = append(sorted, record)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return zonefile.Less(sorted[i], sorted[j])
+	})
+
+	f := hclwrite.NewEmptyFile()
+	root := f.Body()
+	used := make(map[string]bool)
+
+	if len(nameservers) > 0 {
+		label := resourceLabel(domain)
+		used[label] = true
+
+		block := root.AppendNewBlock("resource", []string{"porkbun_domain_nameservers", label}).Body()
+		block.SetAttributeValue("domain", cty.StringVal(domain))
+		values := make([]cty.Value, len(nameservers))
+		for i, ns := range nameservers {
+			values[i] = cty.StringVal(strings.ToLower(strings.TrimSuffix(ns, ".")))
+		}
+		block.SetAttributeValue("nameservers", cty.ListVal(values))
+
+		root.AppendNewline()
+		appendImportBlock(root, "porkbun_domain_nameservers", label, domain)
+	}
+
+	for _, record := range sorted {
+		label := recordResourceLabel(domain, record)
+		for i := 2; used[label]; i++ {
+			label = fmt.Sprintf("%s_%d", recordResourceLabel(domain, record), i)
+		}
+		used[label] = true
+
+		if len(root.Blocks()) > 0 {
+			root.AppendNewline()
+		}
+
+		block := root.AppendNewBlock("resource", []string{"porkbun_dns_record", label}).Body()
+		block.SetAttributeValue("domain", cty.StringVal(domain))
+		if name := porkbun.Subdomain(record.Name, domain); name != "" {
+			block.SetAttributeValue("name", cty.StringVal(porkbun.IDNToUnicode(name)))
+		}
+		block.SetAttributeValue("type", cty.StringVal(record.Type))
+		block.SetAttributeValue("content", cty.StringVal(configContent(record.Type, record.Content)))
+		block.SetAttributeValue("ttl", cty.StringVal(record.TTL))
+		record.Prio = porkbun.PrioOrZero(record.Prio)
+		if record.Type == "MX" || record.Type == "SRV" || record.Prio != "0" {
+			block.SetAttributeValue("prio", cty.StringVal(record.Prio))
+		}
+		if record.Notes != "" {
+			block.SetAttributeValue("notes", cty.StringVal(record.Notes))
+		}
+
+		root.AppendNewline()
+		appendImportBlock(root, "porkbun_dns_record", label, domain+"/"+record.ID)
+	}
+
+	return string(f.Bytes())
+}
+
+// appendImportBlock appends an import block for the resource of
+// resourceType named label to body.
+func appendImportBlock(body *hclwrite.Body, resourceType, label, id string) {
+	block := body.AppendNewBlock("import", nil).Body()
+	block.SetAttributeTraversal("to", hcl.Traversal{
+		hcl.TraverseRoot{Name: resourceType},
+		hcl.TraverseAttr{Name: label},
+	})
+	block.SetAttributeValue("id", cty.StringVal(id))
+}
+
+// recordResourceLabel returns the resource name for record: its subdomain,
+// or "apex", its type and the start of a hash of its content, such as
+// www_a_5d41402a.
+func recordResourceLabel(domain string, record porkbun.DNSRecord) string {
+	name := porkbun.Subdomain(record.Name, domain)
+	switch {
+	case name == "":
+		name = "apex"
+	case name == "*":
+		name = "wildcard"
+	case strings.HasPrefix(name, "*."):
+		name = "wildcard." + name[2:]
+	}
+
+	sum := sha256.Sum256([]byte(record.Content))
+	return resourceLabel(name + "_" + record.Type + "_" + hex.EncodeToString(sum[:4]))
+}
+
+// resourceLabel returns s as a Terraform resource name: in lowercase, with
+// characters other than letters, digits, underscores and dashes replaced
+// by underscores, and starting with a letter or underscore.
+func resourceLabel(s string) string {
+	label := []byte(strings.ToLower(s))
+	for i, c := range label {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+			label[i] = '_'
+		}
+	}
+	if len(label) == 0 || label[0] >= '0' && label[0] <= '9' || label[0] == '-' {
+		label = append([]byte{'_'}, label...)
+	}
+	return string(label)
+}
+
+// configContent returns the content of a record as the porkbun_dns_record
+// resource holds it: TXT values without the character-string quoting, and
+// host names in Unicode.
+func configContent(recordType, content string) string {
+	switch recordType {
+	case "TXT":
+		return porkbun.DecodeTXTContent(content)
+	case "CNAME", "ALIAS", "NS", "MX":
+		return porkbun.IDNToUnicode(content)
+	case "SRV":
+		if fields := strings.Fields(content); len(fields) == 3 {
+			fields[2] = porkbun.IDNToUnicode(fields[2])
+			return strings.Join(fields, " ")
+		}
+	case "HTTPS", "SVCB":
+		if fields := strings.Fields(content); len(fields) >= 2 {
+			fields[1] = porkbun.IDNToUnicode(fields[1])
+			return strings.Join(fields, " ")
+		}
+	}
+	return content
+}
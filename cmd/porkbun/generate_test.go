@@ -0,0 +1,110 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+func TestGenerateConfig(t *testing.T) {
+	records := []porkbun.DNSRecord{
+		{ID: "5", Name: "example.com", Type: "TXT", Content: `v=spf1 include:${domain} -all`, TTL: "600", Prio: "0"},
+		{ID: "4", Name: "example.com", Type: "MX", Content: "mail.example.com", TTL: "3600", Prio: "10"},
+		{ID: "3", Name: "*.example.com", Type: "A", Content: "192.0.2.2", TTL: "600", Prio: "0", Notes: "catch-all"},
+		{ID: "2", Name: "www.example.com", Type: "A", Content: "192.0.2.1", TTL: "600", Prio: "0"},
+		{ID: "1", Name: "example.com", Type: "NS", Content: "curitiba.ns.porkbun.com", TTL: "86400", Prio: "0"},
+	}
+
+	want := `resource "porkbun_domain_nameservers" "example_com" {
+  domain      = "example.com"
+  nameservers = ["curitiba.ns.porkbun.com", "fortaleza.ns.porkbun.com"]
+}
+
+import {
+  to = porkbun_domain_nameservers.example_com
+  id = "example.com"
+}
+
+resource "porkbun_dns_record" "apex_mx_406200cf" {
+  domain  = "example.com"
+  type    = "MX"
+  content = "mail.example.com"
+  ttl     = "3600"
+  prio    = "10"
+}
+
+import {
+  to = porkbun_dns_record.apex_mx_406200cf
+  id = "example.com/4"
+}
+
+resource "porkbun_dns_record" "apex_txt_7673a98e" {
+  domain  = "example.com"
+  type    = "TXT"
+  content = "v=spf1 include:$${domain} -all"
+  ttl     = "600"
+}
+
+import {
+  to = porkbun_dns_record.apex_txt_7673a98e
+  id = "example.com/5"
+}
+
+resource "porkbun_dns_record" "wildcard_a_9a6b2936" {
+  domain  = "example.com"
+  name    = "*"
+  type    = "A"
+  content = "192.0.2.2"
+  ttl     = "600"
+  notes   = "catch-all"
+}
+
+import {
+  to = porkbun_dns_record.wildcard_a_9a6b2936
+  id = "example.com/3"
+}
+
+resource "porkbun_dns_record" "www_a_37fcff24" {
+  domain  = "example.com"
+  name    = "www"
+  type    = "A"
+  content = "192.0.2.1"
+  ttl     = "600"
+}
+
+import {
+  to = porkbun_dns_record.www_a_37fcff24
+  id = "example.com/2"
+}
+`
+
+	got := generateConfig("example.com", records, []string{"curitiba.ns.porkbun.com", "fortaleza.ns.porkbun.com"})
+	if got != want {
+		t.Fatalf("unexpected configuration:\n%s", got)
+	}
+
+	// The addresses do not depend on the order of the records
+	reversed := make([]porkbun.DNSRecord, len(records))
+	for i, record := range records {
+		reversed[len(records)-1-i] = record
+	}
+	if generateConfig("example.com", reversed, []string{"curitiba.ns.porkbun.com", "fortaleza.ns.porkbun.com"}) != want {
+		t.Fatal("expected the same configuration for records in another order")
+	}
+}
+
+func TestResourceLabel(t *testing.T) {
+	tests := map[string]string{
+		"www_a_1234":         "www_a_1234",
+		"_dmarc_TXT_1234":    "_dmarc_txt_1234",
+		"mail.eu_A_1234":     "mail_eu_a_1234",
+		"1st_CNAME_1234":     "_1st_cname_1234",
+		"xn--bcher-kva_A_12": "xn--bcher-kva_a_12",
+	}
+
+	for input, want := range tests {
+		if got := resourceLabel(input); got != want {
+			t.Errorf("resourceLabel(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+// Command porkbun manages the DNS records, name servers and domains of a
+// Porkbun account from the command line, with the same API client, and so
+// the same retries, as the Terraform provider.
+//
+// Credentials are read the way the provider reads them: from the -api-key
+// and -secret-api-key flags, or the PORKBUN_API_KEY and
+// PORKBUN_SECRET_API_KEY environment variables.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+// errUsage is returned when a command is given the wrong arguments, after
+// its usage has been written.
+var errUsage = errors.New("invalid usage")
+
+// command is a subcommand of porkbun, such as "records list".
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(ctx context.Context, c *cli, fs *flag.FlagSet, args []string) error
+}
+
+var commands = []command{
+	{"ping", "", "Check the credentials and show the IP address the API sees", runPing},
+	{"domains list", "", "List the domains in the account", runDomainsList},
+	{"ns get", "DOMAIN", "Show the name servers of a domain", runNSGet},
+	{"ns set", "DOMAIN NAMESERVER...", "Replace the name servers of a domain", runNSSet},
+	{"records list", "DOMAIN", "List the DNS records of a domain", runRecordsList},
+	{"records get", "DOMAIN ID", "Show a DNS record", runRecordsGet},
+	{"records create", "DOMAIN", "Create a DNS record", runRecordsCreate},
+	{"records edit", "DOMAIN ID", "Change a DNS record", runRecordsEdit},
+	{"records delete", "DOMAIN ID", "Delete a DNS record", runRecordsDelete},
+	{"zone export", "DOMAIN", "Write the DNS records of a domain as a BIND zone file", runZoneExport},
+	{"zone import", "DOMAIN FILE", "Create, change and delete DNS records to match a zone file", runZoneImport},
+	{"generate", "DOMAIN", "Write Terraform configuration and import blocks for the records of a domain", runGenerate},
+	{"ddns", "DOMAIN/NAME/TYPE...", "Keep A and AAAA records pointed at the public address of this host", runDDNS},
+	{"webhook", "", "Serve an external-dns webhook provider for the domains in the account", runWebhook},
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr, os.Getenv)
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "porkbun: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// cli holds what the commands read and write, and the flags they share.
+type cli struct {
+	stdin          io.Reader
+	stdout, stderr io.Writer
+	getenv         func(string) string
+
+	apiKey       string
+	secretAPIKey string
+	baseURL      string
+	verbose      bool
+	output       string
+}
+
+// run runs the command named by args.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer, getenv func(string) string) error {
+	c := &cli{stdin: stdin, stdout: stdout, stderr: stderr, getenv: getenv}
+
+	for _, cmd := range commands {
+		words := strings.Fields(cmd.name)
+		if len(args) < len(words) || !slices.Equal(args[:len(words)], words) {
+			continue
+		}
+
+		fs := flag.NewFlagSet("porkbun "+cmd.name, flag.ContinueOnError)
+		fs.SetOutput(stderr)
+		fs.StringVar(&c.apiKey, "api-key", "", "Porkbun API key (default $PORKBUN_API_KEY)")
+		fs.StringVar(&c.secretAPIKey, "secret-api-key", "", "Porkbun secret API key (default $PORKBUN_SECRET_API_KEY)")
+		fs.StringVar(&c.baseURL, "base-url", porkbun.DefaultBaseURL, "base URL of the Porkbun API")
+		fs.BoolVar(&c.verbose, "v", false, "log API requests and retries")
+		fs.Usage = func() {
+			fmt.Fprintf(stderr, "Usage: porkbun %s [flags] %s\n\n%s.\n\nFlags:\n", cmd.name, cmd.args, cmd.summary)
+			fs.PrintDefaults()
+		}
+
+		return cmd.run(ctx, c, fs, args[len(words):])
+	}
+
+	c.usage()
+	if len(args) > 0 && slices.Contains([]string{"help", "-h", "-help", "--help"}, args[0]) {
+		return nil
+	}
+	return errUsage
+}
+
+// usage writes the list of commands.
+func (c *cli) usage() {
+	fmt.Fprint(c.stderr, "Usage: porkbun COMMAND [flags] [arguments]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(c.stderr, "  %-16s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprint(c.stderr, "\nRun porkbun COMMAND -h for the flags of a command.\n")
+}
+
+// outputFlag adds the -o flag to fs, for commands that write structured
+// output.
+func (c *cli) outputFlag(fs *flag.FlagSet) {
+	fs.StringVar(&c.output, "o", "table", "output format: table, json or yaml")
+}
+
+// parse parses the flags in args, and checks that between min and max
+// arguments are left. A max below zero allows any number.
+func (c *cli) parse(fs *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, err
+		}
+		return nil, errUsage
+	}
+
+	if fs.NArg() < min || (max >= 0 && fs.NArg() > max) {
+		fmt.Fprintf(c.stderr, "porkbun: wrong number of arguments\n")
+		fs.Usage()
+		return nil, errUsage
+	}
+
+	if c.output != "" && !slices.Contains(outputFormats, c.output) {
+		return nil, fmt.Errorf("unknown output format %q; expected one of %s", c.output, strings.Join(outputFormats, ", "))
+	}
+
+	return fs.Args(), nil
+}
+
+// logger returns the logger for the API client and for ddns, which writes
+// to stderr, with debug messages only if -v is set.
+func (c *cli) logger() *slog.Logger {
+	level := slog.LevelInfo
+	if c.verbose {
+		level = slog.LevelDebug
+	}
+	return slog.New(slog.NewTextHandler(c.stderr, &slog.HandlerOptions{Level: level}))
+}
+
+// client returns a client with the credentials from the flags or the
+// environment, as the provider reads them, and opts.
+func (c *cli) client(opts ...porkbun.Option) (*porkbun.Client, error) {
+	apiKey := c.getenv("PORKBUN_API_KEY")
+	if c.apiKey != "" {
+		apiKey = c.apiKey
+	}
+
+	secretAPIKey := c.getenv("PORKBUN_SECRET_API_KEY")
+	if c.secretAPIKey != "" {
+		secretAPIKey = c.secretAPIKey
+	}
+
+	if apiKey == "" {
+		return nil, errors.New("missing Porkbun API key; set -api-key or the PORKBUN_API_KEY environment variable")
+	}
+	if secretAPIKey == "" {
+		return nil, errors.New("missing Porkbun secret API key; set -secret-api-key or the PORKBUN_SECRET_API_KEY environment variable")
+	}
+
+	opts = append([]porkbun.Option{porkbun.WithBaseURL(c.baseURL), porkbun.WithLogger(c.logger())}, opts...)
+	return porkbun.NewClient(apiKey, secretAPIKey, opts...), nil
+}
+
+// normalizeDomain returns a domain name in lowercase and without a trailing
+// dot.
+func normalizeDomain(domain string) string {
+	return strings.ToLower(strings.TrimSuffix(domain, "."))
+}
+
+func runPing(ctx context.Context, c *cli, fs *flag.FlagSet, args []string) error {
+	c.outputFlag(fs)
+	if _, err := c.parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	ip, err := client.Ping(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.print(struct {
+		YourIP string `json:"yourIp"`
+	}{ip}, table{
+		header: []string{"YOUR IP"},
+		rows:   [][]string{{ip}},
+	})
+}
+
+func runDomainsList(ctx context.Context, c *cli, fs *flag.FlagSet, args []string) error {
+	c.outputFlag(fs)
+	if _, err := c.parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	domains, err := client.ListAllDomains(ctx)
+	if err != nil {
+		return err
+	}
+
+	t := table{header: []string{"DOMAIN", "STATUS", "CREATED", "EXPIRES"}}
+	for _, d := range domains {
+		t.rows = append(t.rows, []string{d.Domain, d.Status, d.CreateDate, d.ExpireDate})
+	}
+	return c.print(nonNil(domains), t)
+}
+
+func runNSGet(ctx context.Context, c *cli, fs *flag.FlagSet, args []string) error {
+	c.outputFlag(fs)
+	args, err := c.parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	nameservers, err := client.GetNameServers(ctx, normalizeDomain(args[0]))
+	if err != nil {
+		return err
+	}
+
+	t := table{header: []string{"NAME SERVER"}}
+	for _, ns := range nameservers {
+		t.rows = append(t.rows, []string{ns})
+	}
+	return c.print(nonNil(nameservers), t)
+}
+
+func runNSSet(ctx context.Context, c *cli, fs *flag.FlagSet, args []string) error {
+	args, err := c.parse(fs, args, 2, -1)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	return client.UpdateNameServers(ctx, normalizeDomain(args[0]), args[1:])
+}
+
+// nonNil returns s, or an empty slice if s is nil, so that it is written as
+// an empty list rather than null.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/neenaoffline/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+// runTest runs the porkbun command name with args against api, and returns
+// what it wrote to stdout.
+func runTest(t *testing.T, api *porkbuntest.API, name string, args ...string) (string, error) {
+	t.Helper()
+
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	env := map[string]string{
+		"PORKBUN_API_KEY":        "pk1_test",
+		"PORKBUN_SECRET_API_KEY": "sk1_test",
+	}
+
+	args = append(append(strings.Fields(name), "-base-url", server.URL), args...)
+
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), args, strings.NewReader(""), &stdout, &stderr, func(key string) string { return env[key] })
+	return stdout.String(), err
+}
+
+func TestRun_RecordsOutputFormats(t *testing.T) {
+	api := &porkbuntest.API{Records: []porkbun.DNSRecord{
+		{ID: "1", Name: "www.example.com", Type: "A", Content: "192.0.2.1", TTL: "600", Prio: "0"},
+		{ID: "2", Name: "example.com", Type: "TXT", Content: "v=spf1 -all", TTL: "3600", Prio: "0"},
+	}}
+
+	out, err := runTest(t, api, "records list", "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := "ID  NAME             TYPE  TTL   PRIO  CONTENT      NOTES\n" +
+		"1   www.example.com  A     600   0     192.0.2.1    \n" +
+		"2   example.com      TXT   3600  0     v=spf1 -all  \n"
+	if out != want {
+		t.Errorf("unexpected table output:\n%s", out)
+	}
+
+	out, err = runTest(t, api, "records list", "-o", "json", "-type", "txt", "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var records []porkbun.DNSRecord
+	if err := json.Unmarshal([]byte(out), &records); err != nil {
+		t.Fatalf("invalid JSON output: %s\n%s", err, out)
+	}
+	if len(records) != 1 || records[0].ID != "2" {
+		t.Errorf("expected only the TXT record, got %+v", records)
+	}
+
+	out, err = runTest(t, api, "records get", "-o", "yaml", "example.com", "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want = "id: \"1\"\nname: www.example.com\ntype: A\ncontent: 192.0.2.1\nttl: \"600\"\nprio: \"0\"\nnotes: \"\"\n"
+	if out != want {
+		t.Errorf("unexpected YAML output:\n%s", out)
+	}
+
+	if _, err := runTest(t, api, "records list", "-o", "xml", "example.com"); err == nil {
+		t.Error("expected an error for an unknown output format")
+	}
+}
+
+func TestRun_RecordsCreateEditDelete(t *testing.T) {
+	api := &porkbuntest.API{}
+	long := strings.Repeat("k", 300)
+
+	out, err := runTest(t, api, "records create", "-o", "json", "-name", "mail", "-type", "txt", "-content", long, "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if strings.TrimSpace(out) != "{\n  \"id\": \"1\"\n}" {
+		t.Errorf("expected the ID of the new record, got %s", out)
+	}
+	if len(api.Records) != 1 || api.Records[0].Type != "TXT" || porkbun.DecodeTXTContent(api.Records[0].Content) != long ||
+		api.Records[0].Content == long {
+		t.Fatalf("expected a TXT record split into character-strings, got %+v", api.Records)
+	}
+
+	if _, err := runTest(t, api, "records edit", "-ttl", "3600", "-notes", "mail", "example.com", "1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if record := api.Records[0]; record.Name != "mail.example.com" || record.TTL != "3600" || record.Notes != "mail" ||
+		porkbun.DecodeTXTContent(record.Content) != long {
+		t.Fatalf("expected only the TTL and notes to change, got %+v", record)
+	}
+
+	if _, err := runTest(t, api, "records delete", "example.com", "1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(api.Records) != 0 {
+		t.Fatalf("expected the record to be deleted, got %+v", api.Records)
+	}
+
+	if _, err := runTest(t, api, "records create", "-type", "A", "example.com"); err == nil {
+		t.Error("expected an error without -content")
+	}
+}
+
+func TestRun_Credentials(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), []string{"ping"}, nil, &stdout, &stderr, func(string) string { return "" })
+	if err == nil || !strings.Contains(err.Error(), "PORKBUN_API_KEY") {
+		t.Fatalf("expected an error naming PORKBUN_API_KEY, got %v", err)
+	}
+
+	out, err := runTest(t, &porkbuntest.API{}, "ping", "-api-key", "pk1_other")
+	var apiErr *porkbun.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected the -api-key flag to override the environment, got %v (%s)", err, out)
+	}
+
+	out, err = runTest(t, &porkbuntest.API{}, "ping")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if out != "YOUR IP\n192.0.2.1\n" {
+		t.Fatalf("unexpected output %q", out)
+	}
+}
+
+func TestRun_Usage(t *testing.T) {
+	for _, args := range [][]string{nil, {"records"}, {"records", "frobnicate"}, {"ns", "get"}} {
+		var stdout, stderr bytes.Buffer
+		err := run(context.Background(), args, nil, &stdout, &stderr, func(string) string { return "" })
+		if !errors.Is(err, errUsage) {
+			t.Errorf("expected a usage error for %v, got %v", args, err)
+		}
+		if !strings.Contains(stderr.String(), "Usage: porkbun") {
+			t.Errorf("expected usage for %v, got %q", args, stderr.String())
+		}
+	}
+}
+
+func TestRun_ZoneImport(t *testing.T) {
+	api := &porkbuntest.API{
+		NextID: 10,
+		Records: []porkbun.DNSRecord{
+			{ID: "1", Name: "example.com", Type: "NS", Content: "curitiba.ns.porkbun.com", TTL: "86400"},
+			{ID: "2", Name: "www.example.com", Type: "A", Content: "192.0.2.1", TTL: "600"},
+			{ID: "3", Name: "old.example.com", Type: "A", Content: "192.0.2.3", TTL: "600"},
+			{ID: "4", Name: "example.com", Type: "MX", Content: "mail.example.com", TTL: "600", Prio: "10"},
+		},
+	}
+
+	zone := filepath.Join(t.TempDir(), "example.com.zone")
+	err := os.WriteFile(zone, []byte(`$ORIGIN example.com.
+$TTL 600
+@	IN	NS	ns1.example.net.
+@	IN	MX	20 mail.example.com.
+www	IN	A	192.0.2.1
+new	60	IN	A	192.0.2.2
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runTest(t, api, "zone import", "-o", "json", "-prune", "-dry-run", "example.com", zone)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var changes []zoneChange
+	if err := json.Unmarshal([]byte(out), &changes); err != nil {
+		t.Fatalf("invalid JSON output: %s\n%s", err, out)
+	}
+	var got []string
+	for _, change := range changes {
+		got = append(got, fmt.Sprintf("%s %s %s %s %s", change.Action, change.Name, change.Type, change.TTL, change.Prio))
+	}
+	want := "delete old.example.com A 600 ,edit example.com MX 600 20,create new.example.com A 600 0"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("expected changes %s, got %s", want, strings.Join(got, ","))
+	}
+	if len(api.Records) != 4 || api.Records[3].Prio != "10" {
+		t.Fatalf("expected no changes with -dry-run, got %+v", api.Records)
+	}
+
+	if _, err := runTest(t, api, "zone import", "example.com", zone); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var names []string
+	for _, record := range api.Records {
+		names = append(names, record.ID+" "+record.Name+" "+record.Prio)
+	}
+	want = "1 example.com ,2 www.example.com ,3 old.example.com ,4 example.com 20,11 new.example.com 0"
+	if strings.Join(names, ",") != want {
+		t.Fatalf("expected records %s without -prune, got %s", want, strings.Join(names, ","))
+	}
+
+	out, err = runTest(t, api, "zone export", "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(out, "new\t600\tIN\tA\t192.0.2.2\n") || !strings.Contains(out, "@\t600\tIN\tMX\t20 mail.example.com.\n") {
+		t.Fatalf("unexpected zone file:\n%s", out)
+	}
+}
+
+func TestRun_Generate(t *testing.T) {
+	api := &porkbuntest.API{Records: []porkbun.DNSRecord{
+		{ID: "1", Name: "example.com", Type: "NS", Content: "curitiba.ns.porkbun.com", TTL: "86400"},
+		{ID: "2", Name: "www.example.com", Type: "A", Content: "192.0.2.1", TTL: "600"},
+	}}
+
+	out, err := runTest(t, api, "generate", "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, want := range []string{
+		`resource "porkbun_domain_nameservers" "example_com" {`,
+		`resource "porkbun_dns_record" "www_a_37fcff24" {`,
+		`id = "example.com/2"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %s in the configuration:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `id = "example.com/1"`) {
+		t.Errorf("expected the domain's NS record to be left out:\n%s", out)
+	}
+
+	out, err = runTest(t, api, "generate", "-nameservers=false", "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if strings.Contains(out, "porkbun_domain_nameservers") {
+		t.Errorf("expected no name servers with -nameservers=false:\n%s", out)
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"strings"
+
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+// recordFlags are the flags that set the fields of a DNS record.
+type recordFlags struct {
+	name, recordType, content, ttl, prio, notes string
+}
+
+func (f *recordFlags) add(fs *flag.FlagSet) {
+	fs.StringVar(&f.name, "name", "", "subdomain of the record, empty for the domain itself")
+	fs.StringVar(&f.recordType, "type", "", "record type, such as A, CNAME or TXT")
+	fs.StringVar(&f.content, "content", "", "content of the record")
+	fs.StringVar(&f.ttl, "ttl", "", "TTL in seconds (default 600)")
+	fs.StringVar(&f.prio, "prio", "", "priority, for MX and SRV records")
+	fs.StringVar(&f.notes, "notes", "", "notes on the record")
+}
+
+// recordTable returns records as a table.
+func recordTable(records []porkbun.DNSRecord) table {
+	t := table{header: []string{"ID", "NAME", "TYPE", "TTL", "PRIO", "CONTENT", "NOTES"}}
+	for _, r := range records {
+		t.rows = append(t.rows, []string{r.ID, r.Name, r.Type, r.TTL, r.Prio, r.Content, r.Notes})
+	}
+	return t
+}
+
+// recordContent returns content as the API takes it for a record of
+// recordType, with long TXT values split into character-strings as the
+// provider does.
+func recordContent(recordType, content string) string {
+	if recordType == "TXT" {
+		return porkbun.EncodeTXTContent(content)
+	}
+	return content
+}
+
+func runRecordsList(ctx context.Context, c *cli, fs *flag.FlagSet, args []string) error {
+	c.outputFlag(fs)
+	recordType := fs.String("type", "", "only list records of this type")
+	name := fs.String("name", "", "only list records with this subdomain, \"@\" for the domain itself")
+	args, err := c.parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	domain := normalizeDomain(args[0])
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	records, err := client.RetrieveDNSRecords(ctx, domain)
+	if err != nil {
+		return err
+	}
+
+	var listed []porkbun.DNSRecord
+	for _, record := range records {
+		if *recordType != "" && !strings.EqualFold(record.Type, *recordType) {
+			continue
+		}
+		if *name != "" && !strings.EqualFold(porkbun.Subdomain(record.Name, domain), porkbun.Subdomain(strings.TrimPrefix(*name, "@"), domain)) {
+			continue
+		}
+		listed = append(listed, record)
+	}
+
+	return c.print(nonNil(listed), recordTable(listed))
+}
+
+func runRecordsGet(ctx context.Context, c *cli, fs *flag.FlagSet, args []string) error {
+	c.outputFlag(fs)
+	args, err := c.parse(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	record, err := client.GetDNSRecord(ctx, normalizeDomain(args[0]), args[1])
+	if err != nil {
+		return err
+	}
+
+	return c.print(record, recordTable([]porkbun.DNSRecord{*record}))
+}
+
+func runRecordsCreate(ctx context.Context, c *cli, fs *flag.FlagSet, args []string) error {
+	c.outputFlag(fs)
+	var f recordFlags
+	f.add(fs)
+	args, err := c.parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if f.recordType == "" || f.content == "" {
+		return errors.New("-type and -content must be set")
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	recordType := strings.ToUpper(f.recordType)
+	id, err := client.CreateDNSRecord(ctx, normalizeDomain(args[0]), porkbun.CreateDNSRecordRequest{
+		Name:    f.name,
+		Type:    recordType,
+		Content: recordContent(recordType, f.content),
+		TTL:     f.ttl,
+		Prio:    f.prio,
+		Notes:   f.notes,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.print(struct {
+		ID string `json:"id"`
+	}{id}, table{
+		header: []string{"ID"},
+		rows:   [][]string{{id}},
+	})
+}
+
+// runRecordsEdit changes the fields of a record set by flags, and keeps the
+// others, which the API would otherwise clear.
+func runRecordsEdit(ctx context.Context, c *cli, fs *flag.FlagSet, args []string) error {
+	var f recordFlags
+	f.add(fs)
+	args, err := c.parse(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	domain := normalizeDomain(args[0])
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	record, err := client.GetDNSRecord(ctx, domain, args[1])
+	if err != nil {
+		return err
+	}
+
+	req := porkbun.EditDNSRecordRequest{
+		Name:    porkbun.Subdomain(record.Name, domain),
+		Type:    record.Type,
+		Content: record.Content,
+		TTL:     record.TTL,
+		Prio:    record.Prio,
+		Notes:   record.Notes,
+	}
+	set := make(map[string]bool)
+	fs.Visit(func(fl *flag.Flag) { set[fl.Name] = true })
+	if set["name"] {
+		req.Name = f.name
+	}
+	if set["type"] {
+		req.Type = strings.ToUpper(f.recordType)
+	}
+	if set["content"] {
+		req.Content = recordContent(req.Type, f.content)
+	}
+	if set["ttl"] {
+		req.TTL = f.ttl
+	}
+	if set["prio"] {
+		req.Prio = f.prio
+	}
+	if set["notes"] {
+		req.Notes = f.notes
+	}
+
+	return client.EditDNSRecord(ctx, domain, args[1], req)
+}
+
+func runRecordsDelete(ctx context.Context, c *cli, fs *flag.FlagSet, args []string) error {
+	args, err := c.parse(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	return client.DeleteDNSRecord(ctx, normalizeDomain(args[0]), args[1])
+}
@@ -0,0 +1,475 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+// webhookMediaType is the media type of the external-dns webhook protocol.
+const webhookMediaType = "application/external.dns.webhook+json;version=1"
+
+// webhookRecordTypes are the record types the webhook manages.
+var webhookRecordTypes = []string{"A", "AAAA", "CNAME", "TXT", "MX", "SRV", "NS", "CAA"}
+
+// endpoint is an external-dns endpoint: the records of one type for one
+// name.
+type endpoint struct {
+	DNSName          string             `json:"dnsName,omitempty"`
+	Targets          []string           `json:"targets,omitempty"`
+	RecordType       string             `json:"recordType,omitempty"`
+	SetIdentifier    string             `json:"setIdentifier,omitempty"`
+	RecordTTL        int64              `json:"recordTTL,omitempty"`
+	Labels           map[string]string  `json:"labels,omitempty"`
+	ProviderSpecific []providerSpecific `json:"providerSpecific,omitempty"`
+}
+
+type providerSpecific struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// changes are the changes external-dns asks the webhook to make. UpdateOld
+// and UpdateNew hold the same endpoints before and after the update.
+type changes struct {
+	Create    []*endpoint `json:"create,omitempty"`
+	UpdateOld []*endpoint `json:"updateOld,omitempty"`
+	UpdateNew []*endpoint `json:"updateNew,omitempty"`
+	Delete    []*endpoint `json:"delete,omitempty"`
+}
+
+// domainFilter limits the names the webhook manages, as external-dns's
+// --domain-filter and --exclude-domains flags do. A name matches a domain if
+// it is the domain or a name under it.
+type domainFilter struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// match reports whether name is managed.
+func (f domainFilter) match(name string) bool {
+	for _, domain := range f.Exclude {
+		if matchDomain(name, domain) {
+			return false
+		}
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, domain := range f.Include {
+		if matchDomain(name, domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchZone reports whether any name in zone can be managed.
+func (f domainFilter) matchZone(zone string) bool {
+	for _, domain := range f.Exclude {
+		if matchDomain(zone, domain) {
+			return false
+		}
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, domain := range f.Include {
+		if matchDomain(zone, domain) || matchDomain(domain, zone) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchDomain reports whether name is domain or a name under it.
+func matchDomain(name, domain string) bool {
+	return name == domain || strings.HasSuffix(name, "."+domain)
+}
+
+// stringsFlag is a flag that may be given more than once.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, normalizeDomain(value))
+	return nil
+}
+
+// webhook is an external-dns webhook provider for the domains in a Porkbun
+// account.
+type webhook struct {
+	client *porkbun.Client
+	filter domainFilter
+	logger *slog.Logger
+}
+
+func runWebhook(ctx context.Context, c *cli, fs *flag.FlagSet, args []string) error {
+	listen := fs.String("listen", "127.0.0.1:8888", "address to serve the webhook on")
+	var include, exclude stringsFlag
+	fs.Var(&include, "domain-filter", "only manage names under this domain; may be given more than once")
+	fs.Var(&exclude, "exclude-domains", "do not manage names under this domain; may be given more than once")
+	if _, err := c.parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	w := &webhook{
+		client: client,
+		filter: domainFilter{Include: include, Exclude: exclude},
+		logger: c.logger(),
+	}
+	server := &http.Server{
+		Addr:              *listen,
+		Handler:           w.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	w.logger.InfoContext(ctx, "Serving external-dns webhook", "address", *listen)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// handler returns the handler serving the webhook protocol.
+func (w *webhook) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", w.negotiate)
+	mux.HandleFunc("GET /records", w.records)
+	mux.HandleFunc("POST /records", w.applyChanges)
+	mux.HandleFunc("POST /adjustendpoints", w.adjustEndpoints)
+	mux.HandleFunc("GET /healthz", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+	return mux
+}
+
+// negotiate answers external-dns's first request with the domain filter.
+func (w *webhook) negotiate(rw http.ResponseWriter, r *http.Request) {
+	w.respond(rw, r, w.filter)
+}
+
+// records returns the managed records as endpoints.
+func (w *webhook) records(rw http.ResponseWriter, r *http.Request) {
+	zones, err := w.zones(r.Context())
+	if err != nil {
+		w.fail(rw, r, err)
+		return
+	}
+
+	endpoints := []*endpoint{}
+	for _, zone := range zones {
+		records, err := w.client.RetrieveDNSRecords(r.Context(), zone)
+		if err != nil {
+			w.fail(rw, r, err)
+			return
+		}
+		endpoints = append(endpoints, w.endpoints(zone, records)...)
+	}
+
+	w.respond(rw, r, endpoints)
+}
+
+// adjustEndpoints changes the endpoints external-dns wants to what Porkbun
+// will store, so that they do not show as changed on every run: TTLs are
+// raised to the minimum Porkbun accepts, and endpoints of record types
+// Porkbun does not support are dropped.
+func (w *webhook) adjustEndpoints(rw http.ResponseWriter, r *http.Request) {
+	var endpoints []*endpoint
+	if err := json.NewDecoder(r.Body).Decode(&endpoints); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	adjusted := []*endpoint{}
+	for _, ep := range endpoints {
+		if !slices.Contains(webhookRecordTypes, ep.RecordType) {
+			w.logger.WarnContext(r.Context(), "Dropping endpoint of unsupported record type", "name", ep.DNSName, "type", ep.RecordType)
+			continue
+		}
+		ep.DNSName = normalizeDomain(ep.DNSName)
+		if ep.RecordTTL != 0 && ep.RecordTTL < porkbun.MinTTL {
+			ep.RecordTTL = porkbun.MinTTL
+		}
+		adjusted = append(adjusted, ep)
+	}
+
+	w.respond(rw, r, adjusted)
+}
+
+// applyChanges makes the changes external-dns asks for: deletions first, so
+// that a record being replaced does not conflict with its replacement, then
+// updates and creations.
+func (w *webhook) applyChanges(rw http.ResponseWriter, r *http.Request) {
+	var req changes
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	zones, err := w.zones(ctx)
+	if err != nil {
+		w.fail(rw, r, err)
+		return
+	}
+
+	// The records of each zone, read when first needed and kept up to date
+	// with the changes made, so that a name deleted and created again in the
+	// same batch is seen as it is now
+	live := make(map[string][]porkbun.DNSRecord)
+	zoneRecords := func(zone string) ([]porkbun.DNSRecord, error) {
+		if records, ok := live[zone]; ok {
+			return records, nil
+		}
+		records, err := w.client.RetrieveDNSRecords(ctx, zone)
+		if err != nil {
+			return nil, err
+		}
+		live[zone] = records
+		return records, nil
+	}
+
+	apply := func(ep *endpoint, remove []string) error {
+		name := normalizeDomain(ep.DNSName)
+		zone := zoneFor(name, zones)
+		if zone == "" || !w.filter.match(name) {
+			return fmt.Errorf("%s is not in a managed domain", name)
+		}
+		if _, err := zoneRecords(zone); err != nil {
+			return err
+		}
+
+		for _, target := range remove {
+			if record, ok := findEndpointRecord(live[zone], name, ep.RecordType, target); ok {
+				if err := w.client.DeleteDNSRecord(ctx, zone, record.ID); err != nil {
+					return err
+				}
+				live[zone] = slices.DeleteFunc(live[zone], func(r porkbun.DNSRecord) bool {
+					return r.ID == record.ID
+				})
+			}
+		}
+
+		// Targets that already have a record, such as when external-dns
+		// sends a create again after a response was lost, are only changed
+		// if their TTL differs
+		ttl := endpointTTL(ep.RecordTTL)
+		for _, target := range ep.Targets {
+			content, prio := recordFields(ep.RecordType, target)
+			record, ok := findEndpointRecord(live[zone], name, ep.RecordType, target)
+
+			switch {
+			case !ok:
+				id, err := w.client.CreateDNSRecordWithExisting(ctx, zone, porkbun.CreateDNSRecordRequest{
+					Name:    porkbun.Subdomain(name, zone),
+					Type:    ep.RecordType,
+					Content: content,
+					TTL:     ttl,
+					Prio:    prio,
+				}, live[zone])
+				if err != nil {
+					return err
+				}
+				live[zone] = append(live[zone], porkbun.DNSRecord{ID: id, Name: name, Type: ep.RecordType, Content: content, TTL: ttl, Prio: prio})
+
+			case ttl != "" && record.TTL != ttl:
+				err := w.client.EditDNSRecord(ctx, zone, record.ID, porkbun.EditDNSRecordRequest{
+					Name:    porkbun.Subdomain(name, zone),
+					Type:    ep.RecordType,
+					Content: record.Content,
+					TTL:     ttl,
+					Prio:    prio,
+					Notes:   record.Notes,
+				})
+				if err != nil {
+					return err
+				}
+				for i := range live[zone] {
+					if live[zone][i].ID == record.ID {
+						live[zone][i].TTL = ttl
+					}
+				}
+			}
+		}
+		return nil
+	}
+
+	var errs []error
+	for _, ep := range req.Delete {
+		if err := apply(&endpoint{DNSName: ep.DNSName, RecordType: ep.RecordType}, ep.Targets); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete %s %s: %w", ep.RecordType, ep.DNSName, err))
+		}
+	}
+	for _, ep := range req.UpdateNew {
+		var old []string
+		for _, o := range req.UpdateOld {
+			if normalizeDomain(o.DNSName) == normalizeDomain(ep.DNSName) && o.RecordType == ep.RecordType && o.SetIdentifier == ep.SetIdentifier {
+				old = o.Targets
+			}
+		}
+		var remove []string
+		for _, target := range old {
+			if !slices.Contains(ep.Targets, target) {
+				remove = append(remove, target)
+			}
+		}
+		if err := apply(ep, remove); err != nil {
+			errs = append(errs, fmt.Errorf("failed to update %s %s: %w", ep.RecordType, ep.DNSName, err))
+		}
+	}
+	for _, ep := range req.Create {
+		if err := apply(ep, nil); err != nil {
+			errs = append(errs, fmt.Errorf("failed to create %s %s: %w", ep.RecordType, ep.DNSName, err))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		w.fail(rw, r, err)
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// zones returns the domains in the account with names the filter matches.
+func (w *webhook) zones(ctx context.Context) ([]string, error) {
+	domains, err := w.client.ListAllDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var zones []string
+	for _, d := range domains {
+		if zone := normalizeDomain(d.Domain); w.filter.matchZone(zone) {
+			zones = append(zones, zone)
+		}
+	}
+	return zones, nil
+}
+
+// endpoints returns the managed records of zone as endpoints, one for each
+// name and type. The domain's own NS records are left out, as they are
+// managed by Porkbun or porkbun_domain_nameservers.
+func (w *webhook) endpoints(zone string, records []porkbun.DNSRecord) []*endpoint {
+	var endpoints []*endpoint
+	byKey := make(map[string]*endpoint)
+
+	for _, record := range records {
+		name := normalizeDomain(record.Name)
+		if !slices.Contains(webhookRecordTypes, record.Type) || (record.Type == "NS" && name == zone) || !w.filter.match(name) {
+			continue
+		}
+
+		key := name + " " + record.Type
+		ep, ok := byKey[key]
+		if !ok {
+			ttl, _ := strconv.ParseInt(record.TTL, 10, 64)
+			ep = &endpoint{DNSName: name, RecordType: record.Type, RecordTTL: ttl}
+			byKey[key] = ep
+			endpoints = append(endpoints, ep)
+		}
+		ep.Targets = append(ep.Targets, endpointTarget(record))
+	}
+	return endpoints
+}
+
+// endpointTarget returns the content of record as an external-dns target:
+// with the priority in front for MX and SRV records, and TXT values that
+// were split into character-strings joined back together.
+func endpointTarget(record porkbun.DNSRecord) string {
+	switch record.Type {
+	case "MX", "SRV":
+		return porkbun.PrioOrZero(record.Prio) + " " + record.Content
+	case "TXT":
+		if strs, ok := porkbun.ParseTXTStrings(record.Content); ok && len(strs) > 1 {
+			return strings.Join(strs, "")
+		}
+	}
+	return record.Content
+}
+
+// recordFields returns the content and priority of a record for an
+// external-dns target.
+func recordFields(recordType, target string) (content, prio string) {
+	switch recordType {
+	case "MX", "SRV":
+		if prio, content, ok := strings.Cut(target, " "); ok {
+			return content, prio
+		}
+	case "TXT":
+		return porkbun.EncodeTXTContent(target), ""
+	}
+	return target, ""
+}
+
+// findEndpointRecord returns the record for target among records.
+func findEndpointRecord(records []porkbun.DNSRecord, name, recordType, target string) (porkbun.DNSRecord, bool) {
+	content, prio := recordFields(recordType, target)
+	for _, record := range records {
+		if normalizeDomain(record.Name) == name && record.Type == recordType &&
+			porkbun.SameContent(recordType, record.Content, content) && (prio == "" || porkbun.PrioOrZero(record.Prio) == prio) {
+			return record, true
+		}
+	}
+	return porkbun.DNSRecord{}, false
+}
+
+// endpointTTL returns the TTL of an endpoint as the API takes it, raised to
+// the minimum, or empty for the default if external-dns does not set one.
+func endpointTTL(ttl int64) string {
+	if ttl == 0 {
+		return ""
+	}
+	return strconv.FormatInt(max(ttl, porkbun.MinTTL), 10)
+}
+
+// zoneFor returns the longest of zones that name is in, or "" if it is in
+// none of them.
+func zoneFor(name string, zones []string) string {
+	found := ""
+	for _, zone := range zones {
+		if matchDomain(name, zone) && len(zone) > len(found) {
+			found = zone
+		}
+	}
+	return found
+}
+
+// respond writes v as a webhook response.
+func (w *webhook) respond(rw http.ResponseWriter, r *http.Request, v interface{}) {
+	rw.Header().Set("Content-Type", webhookMediaType)
+	if err := json.NewEncoder(rw).Encode(v); err != nil {
+		w.logger.ErrorContext(r.Context(), "Failed to write response", "path", r.URL.Path, "error", err)
+	}
+}
+
+// fail logs err and responds with it.
+func (w *webhook) fail(rw http.ResponseWriter, r *http.Request, err error) {
+	w.logger.ErrorContext(r.Context(), "Request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+	http.Error(rw, err.Error(), http.StatusInternalServerError)
+}
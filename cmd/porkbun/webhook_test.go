@@ -0,0 +1,276 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/neenaoffline/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+// newTestWebhook returns a webhook server for the domains of api.
+func newTestWebhook(t *testing.T, api *porkbuntest.API, filter domainFilter) *httptest.Server {
+	t.Helper()
+
+	apiServer := httptest.NewServer(api)
+	t.Cleanup(apiServer.Close)
+
+	w := &webhook{
+		client: porkbun.NewClient("pk1_test", "sk1_test", porkbun.WithBaseURL(apiServer.URL)),
+		filter: filter,
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	server := httptest.NewServer(w.handler())
+	t.Cleanup(server.Close)
+	return server
+}
+
+// webhookRequest makes a request to the webhook, and decodes the response
+// into v if it is not nil.
+func webhookRequest(t *testing.T, server *httptest.Server, method, path string, body, v interface{}) int {
+	t.Helper()
+
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r = strings.NewReader(string(data))
+	}
+
+	req, err := http.NewRequest(method, server.URL+path, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept", webhookMediaType)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if v != nil {
+		if ct := resp.Header.Get("Content-Type"); ct != webhookMediaType {
+			t.Fatalf("expected content type %s, got %s", webhookMediaType, ct)
+		}
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("invalid response: %s", err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestWebhook_Negotiate(t *testing.T) {
+	server := newTestWebhook(t, &porkbuntest.API{}, domainFilter{Include: []string{"example.com"}, Exclude: []string{"internal.example.com"}})
+
+	var filter map[string][]string
+	if status := webhookRequest(t, server, "GET", "/", nil, &filter); status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+	if fmt.Sprint(filter) != "map[exclude:[internal.example.com] include:[example.com]]" {
+		t.Fatalf("unexpected domain filter %v", filter)
+	}
+}
+
+func TestWebhook_Records(t *testing.T) {
+	long := strings.Repeat("k", 300)
+	api := &porkbuntest.API{Records: []porkbun.DNSRecord{
+		{ID: "1", Name: "example.com", Type: "NS", Content: "curitiba.ns.porkbun.com", TTL: "86400"},
+		{ID: "2", Name: "www.example.com", Type: "A", Content: "192.0.2.1", TTL: "600"},
+		{ID: "3", Name: "www.example.com", Type: "A", Content: "192.0.2.2", TTL: "600"},
+		{ID: "4", Name: "example.com", Type: "MX", Content: "mail.example.com", TTL: "3600", Prio: "10"},
+		{ID: "5", Name: "www.example.com", Type: "TXT", Content: `"heritage=external-dns,external-dns/owner=default"`, TTL: "600"},
+		{ID: "6", Name: "dkim.example.com", Type: "TXT", Content: porkbun.EncodeTXTContent(long), TTL: "600"},
+		{ID: "7", Name: "db.internal.example.com", Type: "A", Content: "10.0.0.1", TTL: "600"},
+		{ID: "8", Name: "example.com", Type: "ALIAS", Content: "lb.example.net", TTL: "600"},
+	}}
+	server := newTestWebhook(t, api, domainFilter{Exclude: []string{"internal.example.com"}})
+
+	var endpoints []endpoint
+	if status := webhookRequest(t, server, "GET", "/records", nil, &endpoints); status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+
+	var got []string
+	for _, ep := range endpoints {
+		got = append(got, fmt.Sprintf("%s %s %d %s", ep.DNSName, ep.RecordType, ep.RecordTTL, strings.Join(ep.Targets, ",")))
+	}
+	want := []string{
+		"www.example.com A 600 192.0.2.1,192.0.2.2",
+		"example.com MX 3600 10 mail.example.com",
+		`www.example.com TXT 600 "heritage=external-dns,external-dns/owner=default"`,
+		"dkim.example.com TXT 600 " + long,
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected endpoints\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestWebhook_ApplyChanges(t *testing.T) {
+	api := &porkbuntest.API{
+		NextID: 10,
+		Records: []porkbun.DNSRecord{
+			{ID: "1", Name: "app.example.com", Type: "A", Content: "192.0.2.1", TTL: "600"},
+			{ID: "2", Name: "app.example.com", Type: "A", Content: "192.0.2.2", TTL: "600"},
+			{ID: "3", Name: "old.example.com", Type: "CNAME", Content: "app.example.com", TTL: "600"},
+			{ID: "4", Name: "old.example.com", Type: "TXT", Content: `"heritage=external-dns,external-dns/owner=default"`, TTL: "600"},
+		},
+	}
+	server := newTestWebhook(t, api, domainFilter{Include: []string{"example.com"}})
+
+	owner := `"heritage=external-dns,external-dns/owner=default,external-dns/resource=ingress/default/web"`
+	status := webhookRequest(t, server, "POST", "/records", changes{
+		Create: []*endpoint{
+			{DNSName: "web.example.com", RecordType: "A", Targets: []string{"192.0.2.10"}, RecordTTL: 300},
+			{DNSName: "web.example.com", RecordType: "TXT", Targets: []string{owner}},
+			{DNSName: "example.com", RecordType: "MX", Targets: []string{"10 mail.example.com"}},
+		},
+		UpdateOld: []*endpoint{
+			{DNSName: "app.example.com", RecordType: "A", Targets: []string{"192.0.2.1", "192.0.2.2"}, RecordTTL: 600},
+		},
+		UpdateNew: []*endpoint{
+			{DNSName: "app.example.com", RecordType: "A", Targets: []string{"192.0.2.2", "192.0.2.3"}, RecordTTL: 3600},
+		},
+		Delete: []*endpoint{
+			{DNSName: "old.example.com", RecordType: "CNAME", Targets: []string{"app.example.com"}},
+			{DNSName: "old.example.com", RecordType: "TXT", Targets: []string{`"heritage=external-dns,external-dns/owner=default"`}},
+		},
+	}, nil)
+	if status != http.StatusNoContent {
+		t.Fatalf("unexpected status %d", status)
+	}
+
+	var got []string
+	for _, record := range api.Records {
+		got = append(got, fmt.Sprintf("%s %s %s %s %s", record.ID, record.Name, record.Type, record.TTL, endpointTarget(record)))
+	}
+	want := []string{
+		"2 app.example.com A 3600 192.0.2.2",
+		"11 app.example.com A 3600 192.0.2.3",
+		"12 web.example.com A 600 192.0.2.10",
+		"13 web.example.com TXT 600 " + owner,
+		"14 example.com MX 600 10 mail.example.com",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected records\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	// Names outside the domain filter are refused
+	status = webhookRequest(t, server, "POST", "/records", changes{
+		Create: []*endpoint{{DNSName: "www.example.net", RecordType: "A", Targets: []string{"192.0.2.1"}}},
+	}, nil)
+	if status != http.StatusInternalServerError {
+		t.Fatalf("expected an error for a name outside the managed domains, got status %d", status)
+	}
+}
+
+func TestWebhook_ApplyChangesAgain(t *testing.T) {
+	api := &porkbuntest.API{
+		NextID: 10,
+		Records: []porkbun.DNSRecord{
+			{ID: "1", Name: "app.example.com", Type: "A", Content: "192.0.2.1", TTL: "600"},
+		},
+	}
+	server := newTestWebhook(t, api, domainFilter{Include: []string{"example.com"}})
+
+	records := func() []string {
+		var got []string
+		for _, record := range api.Records {
+			got = append(got, fmt.Sprintf("%s %s %s %s %s", record.ID, record.Name, record.Type, record.TTL, endpointTarget(record)))
+		}
+		return got
+	}
+
+	// A create sent again, as external-dns does when it did not get the
+	// response to the first one, does not add a second record
+	create := changes{
+		Create: []*endpoint{{DNSName: "web.example.com", RecordType: "A", Targets: []string{"192.0.2.10"}}},
+	}
+	for i := 0; i < 2; i++ {
+		if status := webhookRequest(t, server, "POST", "/records", create, nil); status != http.StatusNoContent {
+			t.Fatalf("unexpected status %d", status)
+		}
+	}
+
+	// Nor does one for a record that exists, but its TTL is changed
+	create.Create[0].RecordTTL = 3600
+	if status := webhookRequest(t, server, "POST", "/records", create, nil); status != http.StatusNoContent {
+		t.Fatalf("unexpected status %d", status)
+	}
+
+	// A record deleted and created again in the same batch is replaced
+	status := webhookRequest(t, server, "POST", "/records", changes{
+		Create: []*endpoint{{DNSName: "app.example.com", RecordType: "A", Targets: []string{"192.0.2.1"}}},
+		Delete: []*endpoint{{DNSName: "app.example.com", RecordType: "A", Targets: []string{"192.0.2.1"}}},
+	}, nil)
+	if status != http.StatusNoContent {
+		t.Fatalf("unexpected status %d", status)
+	}
+
+	want := []string{
+		"11 web.example.com A 3600 192.0.2.10",
+		"12 app.example.com A 600 192.0.2.1",
+	}
+	if got := records(); !slices.Equal(got, want) {
+		t.Fatalf("expected records\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestWebhook_AdjustEndpoints(t *testing.T) {
+	server := newTestWebhook(t, &porkbuntest.API{}, domainFilter{})
+
+	var endpoints []endpoint
+	status := webhookRequest(t, server, "POST", "/adjustendpoints", []*endpoint{
+		{DNSName: "WWW.example.com.", RecordType: "A", Targets: []string{"192.0.2.1"}, RecordTTL: 60},
+		{DNSName: "app.example.com", RecordType: "A", Targets: []string{"192.0.2.1"}},
+		{DNSName: "example.com", RecordType: "PTR", Targets: []string{"host.example.com"}},
+	}, &endpoints)
+	if status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+
+	if len(endpoints) != 2 {
+		t.Fatalf("expected the PTR endpoint to be dropped, got %+v", endpoints)
+	}
+	if endpoints[0].DNSName != "www.example.com" || endpoints[0].RecordTTL != porkbun.MinTTL {
+		t.Errorf("expected a normalized name and the minimum TTL, got %+v", endpoints[0])
+	}
+	if endpoints[1].RecordTTL != 0 {
+		t.Errorf("expected an unset TTL to stay unset, got %+v", endpoints[1])
+	}
+}
+
+func TestDomainFilter(t *testing.T) {
+	filter := domainFilter{Include: []string{"k8s.example.com", "example.org"}, Exclude: []string{"internal.k8s.example.com"}}
+
+	for name, want := range map[string]bool{
+		"k8s.example.com":             true,
+		"web.k8s.example.com":         true,
+		"db.internal.k8s.example.com": false,
+		"example.com":                 false,
+		"notk8s.example.com":          false,
+		"www.example.org":             true,
+	} {
+		if got := filter.match(name); got != want {
+			t.Errorf("match(%q) = %t, want %t", name, got, want)
+		}
+	}
+
+	for zone, want := range map[string]bool{
+		"example.com": true,
+		"example.org": true,
+		"example.net": false,
+	} {
+		if got := filter.matchZone(zone); got != want {
+			t.Errorf("matchZone(%q) = %t, want %t", zone, got, want)
+		}
+	}
+}
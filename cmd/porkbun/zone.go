@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/neenaoffline/terraform-provider-porkbun/internal/zonefile"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+func runZoneExport(ctx context.Context, c *cli, fs *flag.FlagSet, args []string) error {
+	ttl := fs.Int("ttl", 600, "TTL written as the $TTL directive")
+	args, err := c.parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	domain := normalizeDomain(args[0])
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	records, err := client.RetrieveDNSRecords(ctx, domain)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(c.stdout, zonefile.Render(domain, strconv.Itoa(*ttl), records))
+	return err
+}
+
+// zoneChange is a change zone import makes, or would make with -dry-run.
+type zoneChange struct {
+	Action string `json:"action"`
+	porkbun.DNSRecord
+}
+
+// runZoneImport makes the records of a domain match a zone file, the way the
+// porkbun_zone_file_records resource does: records in the file that exist
+// are kept, or changed if only their TTL, priority or notes differ, and the
+// others created. Records not in the file are deleted only with -prune. The
+// name servers of the domain itself are never touched.
+func runZoneImport(ctx context.Context, c *cli, fs *flag.FlagSet, args []string) error {
+	c.outputFlag(fs)
+	prune := fs.Bool("prune", false, "delete records that are not in the zone file")
+	dryRun := fs.Bool("dry-run", false, "show the changes without making them")
+	args, err := c.parse(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	domain := normalizeDomain(args[0])
+
+	var text []byte
+	if args[1] == "-" {
+		text, err = io.ReadAll(c.stdin)
+	} else {
+		text, err = os.ReadFile(args[1])
+	}
+	if err != nil {
+		return err
+	}
+
+	parsed, warnings, err := zonefile.Parse(string(text), domain)
+	if err != nil {
+		return fmt.Errorf("invalid zone file: %w", err)
+	}
+	desired := make([]porkbun.DNSRecord, len(parsed))
+	for i, record := range parsed {
+		desired[i] = record.DNSRecord
+	}
+	for _, warning := range warnings {
+		fmt.Fprintf(c.stderr, "warning: %s\n", warning)
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	live, err := client.RetrieveDNSRecords(ctx, domain)
+	if err != nil {
+		return err
+	}
+
+	changes, kept := planZoneImport(domain, desired, live, *prune)
+	if kept > 0 {
+		fmt.Fprintf(c.stderr, "keeping %d records that are not in the zone file; use -prune to delete them\n", kept)
+	}
+
+	if !*dryRun {
+		for i, change := range changes {
+			if err := applyZoneChange(ctx, client, domain, &changes[i], live); err != nil {
+				return fmt.Errorf("failed to %s %s record %s: %w", change.Action, change.Type, change.Name, err)
+			}
+			if change.Action == "create" {
+				live = append(live, changes[i].DNSRecord)
+			}
+		}
+	}
+
+	t := table{header: []string{"ACTION", "ID", "NAME", "TYPE", "TTL", "PRIO", "CONTENT"}}
+	for _, change := range changes {
+		t.rows = append(t.rows, []string{change.Action, change.ID, change.Name, change.Type, change.TTL, change.Prio, change.Content})
+	}
+	return c.print(nonNil(changes), t)
+}
+
+// planZoneImport returns the changes that make the live records of domain
+// match desired, deletions first so that a record being replaced does not
+// conflict with its replacement, and how many live records not in desired
+// are kept because prune is false.
+func planZoneImport(domain string, desired, live []porkbun.DNSRecord, prune bool) ([]zoneChange, int) {
+	matched := make([]bool, len(live))
+	var edits, creates []zoneChange
+
+	for _, record := range desired {
+		if ttl, _ := strconv.Atoi(record.TTL); ttl < porkbun.MinTTL {
+			record.TTL = strconv.Itoa(porkbun.MinTTL)
+		}
+
+		found := false
+		for i, l := range live {
+			if matched[i] || !strings.EqualFold(porkbun.Subdomain(l.Name, domain), porkbun.Subdomain(record.Name, domain)) || l.Type != record.Type ||
+				!porkbun.SameContent(record.Type, l.Content, record.Content) {
+				continue
+			}
+			matched[i], found = true, true
+
+			if l.TTL != record.TTL || porkbun.PrioOrZero(l.Prio) != porkbun.PrioOrZero(record.Prio) || l.Notes != record.Notes {
+				record.ID = l.ID
+				record.Content = l.Content
+				edits = append(edits, zoneChange{Action: "edit", DNSRecord: record})
+			}
+			break
+		}
+		if !found {
+			creates = append(creates, zoneChange{Action: "create", DNSRecord: record})
+		}
+	}
+
+	var changes []zoneChange
+	kept := 0
+	for i, l := range live {
+		if matched[i] || (l.Type == "NS" && porkbun.Subdomain(l.Name, domain) == "") {
+			continue
+		}
+		if !prune {
+			kept++
+			continue
+		}
+		changes = append(changes, zoneChange{Action: "delete", DNSRecord: l})
+	}
+
+	return append(append(changes, edits...), creates...), kept
+}
+
+// applyZoneChange makes change, and sets the ID of a created record. live
+// are the records of domain before the change.
+func applyZoneChange(ctx context.Context, client *porkbun.Client, domain string, change *zoneChange, live []porkbun.DNSRecord) error {
+	switch change.Action {
+	case "delete":
+		return client.DeleteDNSRecord(ctx, domain, change.ID)
+
+	case "edit":
+		return client.EditDNSRecord(ctx, domain, change.ID, porkbun.EditDNSRecordRequest{
+			Name:    porkbun.Subdomain(change.Name, domain),
+			Type:    change.Type,
+			Content: change.Content,
+			TTL:     change.TTL,
+			Prio:    change.Prio,
+			Notes:   change.Notes,
+		})
+
+	default:
+		id, err := client.CreateDNSRecordWithExisting(ctx, domain, porkbun.CreateDNSRecordRequest{
+			Name:    porkbun.Subdomain(change.Name, domain),
+			Type:    change.Type,
+			Content: change.Content,
+			TTL:     change.TTL,
+			Prio:    change.Prio,
+			Notes:   change.Notes,
+		}, live)
+		change.ID = id
+		return err
+	}
+}
@@ -0,0 +1,150 @@
+// Package porkbuntest provides an in-memory Porkbun API for the tests of
+// the packages that call it.
+package porkbuntest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+// Domain is the only domain the API has.
+const Domain = "example.com"
+
+// API is an in-memory Porkbun API with the DNS records of example.com. It
+// accepts the API keys pk1_test and sk1_test. Serve it with
+// httptest.NewServer and point a client at it with porkbun.WithBaseURL.
+type API struct {
+	// Records are the DNS records of the domain
+	Records []porkbun.DNSRecord
+	// NextID is the ID of the last record created; the next one is given
+	// the one after it
+	NextID int
+	// Requests is the number of requests the API has had
+	Requests int
+
+	mu sync.Mutex
+}
+
+func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.Requests++
+
+	var body map[string]string
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	if body["apikey"] != "pk1_test" || body["secretapikey"] != "sk1_test" {
+		fmt.Fprint(w, `{"status":"ERROR","message":"Invalid API key."}`)
+		return
+	}
+
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(path) > 2 && (path[0] == "dns" || path[0] == "domain") && path[2] != Domain {
+		fmt.Fprint(w, `{"status":"ERROR","message":"Invalid domain."}`)
+		return
+	}
+	resp := map[string]interface{}{"status": "SUCCESS"}
+
+	switch {
+	case path[0] == "ping":
+		resp["yourIp"] = "192.0.2.1"
+
+	case path[0] == "domain" && path[1] == "listAll":
+		resp["domains"] = []porkbun.Domain{{Domain: Domain, Status: "ACTIVE"}}
+
+	case path[0] == "domain" && path[1] == "getNs":
+		resp["ns"] = []string{"curitiba.ns.porkbun.com", "fortaleza.ns.porkbun.com"}
+
+	case path[0] == "dns" && path[1] == "retrieve":
+		records := []porkbun.DNSRecord{}
+		for _, record := range a.Records {
+			if len(path) < 4 || record.ID == path[3] {
+				records = append(records, record)
+			}
+		}
+		resp["records"] = records
+
+	case path[0] == "dns" && path[1] == "retrieveByNameType":
+		name := Domain
+		if len(path) > 4 {
+			name = path[4] + "." + Domain
+		}
+		records := []porkbun.DNSRecord{}
+		for _, record := range a.Records {
+			if record.Type == path[3] && record.Name == name {
+				records = append(records, record)
+			}
+		}
+		resp["records"] = records
+
+	case path[0] == "dns" && path[1] == "create":
+		a.NextID++
+		a.Records = append(a.Records, record(fmt.Sprint(a.NextID), body))
+		resp["id"] = a.NextID
+
+	case path[0] == "dns" && path[1] == "edit":
+		for i, r := range a.Records {
+			if r.ID == path[3] {
+				a.Records[i] = record(r.ID, body)
+			}
+		}
+
+	case path[0] == "dns" && path[1] == "delete":
+		for i, r := range a.Records {
+			if r.ID == path[3] {
+				a.Records = append(a.Records[:i], a.Records[i+1:]...)
+				break
+			}
+		}
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// Summary returns the records of the API, one per line, in the form the API
+// keeps them: ID, name, type, TTL, priority and content.
+func (a *API) Summary() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var lines []string
+	for _, record := range a.Records {
+		lines = append(lines, fmt.Sprintf("%s %s %s %s %s %s", record.ID, record.Name, record.Type, record.TTL, record.Prio, record.Content))
+	}
+	return lines
+}
+
+// record returns the record a create or edit request body describes, with
+// the defaults the API fills in.
+func record(id string, body map[string]string) porkbun.DNSRecord {
+	name := Domain
+	if body["name"] != "" {
+		name = body["name"] + "." + Domain
+	}
+	ttl := body["ttl"]
+	if ttl == "" {
+		ttl = "600"
+	}
+	prio := body["prio"]
+	if prio == "" {
+		prio = "0"
+	}
+	return porkbun.DNSRecord{
+		ID:      id,
+		Name:    name,
+		Type:    body["type"],
+		Content: body["content"],
+		TTL:     ttl,
+		Prio:    prio,
+		Notes:   body["notes"],
+	}
+}
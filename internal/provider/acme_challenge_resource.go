@@ -0,0 +1,337 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ACMEChallengeResource{}
+
+// defaultACMEChallengeTimeout applies to create and delete when the timeouts
+// block does not set one. Create includes waiting for the record to be
+// served by every authoritative name server.
+const defaultACMEChallengeTimeout = 10 * time.Minute
+
+// acmeChallengeLabel is the label ACME DNS-01 challenges are published
+// under, per RFC 8555 section 8.4.
+const acmeChallengeLabel = "_acme-challenge"
+
+func NewACMEChallengeResource() resource.Resource {
+	return &ACMEChallengeResource{}
+}
+
+// ACMEChallengeResource defines the resource implementation.
+type ACMEChallengeResource struct {
+	client *porkbun.Client
+	dns    *dnsQuerier
+}
+
+// ACMEChallengeResourceModel describes the resource data model.
+type ACMEChallengeResourceModel struct {
+	ID               types.String   `tfsdk:"id"`
+	FQDN             IDNStringValue `tfsdk:"fqdn"`
+	KeyAuthorization types.String   `tfsdk:"key_authorization"`
+	Domain           types.String   `tfsdk:"domain"`
+	Name             types.String   `tfsdk:"name"`
+	RecordFQDN       types.String   `tfsdk:"record_fqdn"`
+	Value            types.String   `tfsdk:"value"`
+	Timeouts         timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *ACMEChallengeResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_acme_challenge"
+}
+
+func (r *ACMEChallengeResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Publishes an ACME DNS-01 challenge as a TXT record in Porkbun, waits until every authoritative " +
+			"name server serves it, and removes it again on destroy.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The ID of the TXT record.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"fqdn": schema.StringAttribute{
+				Description: "The name being validated (e.g., www.example.com). A leading *. for wildcard names is ignored. " +
+					"Internationalized names may be written in Unicode.",
+				Required:   true,
+				CustomType: IDNStringType{},
+				Validators: []validator.String{
+					validIDN(),
+				},
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceUnlessIDNEqual(),
+				},
+			},
+			"key_authorization": schema.StringAttribute{
+				Description: "The key authorization for the challenge. The record holds its SHA-256 digest.",
+				Required:    true,
+				Sensitive:   true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"domain": schema.StringAttribute{
+				Description: "The domain in the Porkbun account the record was created in.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "The subdomain of the TXT record, relative to domain.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"record_fqdn": schema.StringAttribute{
+				Description: "The fully qualified name of the TXT record (e.g., _acme-challenge.www.example.com).",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"value": schema.StringAttribute{
+				Description: "The content of the TXT record.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *ACMEChallengeResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = data.client
+	r.dns = data.dns
+}
+
+func (r *ACMEChallengeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ACMEChallengeResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultACMEChallengeTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	domains, err := r.client.ListAllDomains(ctx)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "Unable to list domains", err)
+		return
+	}
+
+	fqdn := strings.TrimPrefix(normalizeHostName(data.FQDN.ValueASCII()), "*.")
+	domain, ok := domainForName(domains, fqdn)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Domain Not Found",
+			fmt.Sprintf("%s is not within any domain in this Porkbun account.", fqdn),
+		)
+		return
+	}
+
+	name := acmeChallengeLabel
+	if sub := porkbun.Subdomain(fqdn, domain); sub != "" {
+		name += "." + sub
+	}
+
+	createReq := porkbun.CreateDNSRecordRequest{
+		Name:    name,
+		Type:    "TXT",
+		Content: acmeChallengeValue(data.KeyAuthorization.ValueString()),
+		TTL:     "600",
+	}
+
+	tflog.Debug(ctx, "Creating ACME challenge record", map[string]interface{}{
+		"domain": domain,
+		"name":   name,
+	})
+
+	id, err := r.client.CreateDNSRecord(ctx, domain, createReq)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "Unable to create ACME challenge record", err)
+		return
+	}
+
+	data.ID = types.StringValue(id)
+	data.Domain = types.StringValue(domain)
+	data.Name = types.StringValue(name)
+	data.RecordFQDN = types.StringValue(name + "." + domain)
+	data.Value = types.StringValue(createReq.Content)
+
+	// Save data into Terraform state before waiting, so the record is
+	// cleaned up even if it never propagates.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	nameservers, err := r.client.GetNameServers(ctx, domain)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "Unable to read name servers to check propagation", err)
+		return
+	}
+
+	err = r.dns.waitForRecord(ctx, nameservers, data.RecordFQDN.ValueString(), "TXT", createReq.Content, "", defaultPropagationInterval)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "ACME challenge record did not propagate", err)
+		return
+	}
+}
+
+func (r *ACMEChallengeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ACMEChallengeResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	record, err := r.client.GetDNSRecord(ctx, data.Domain.ValueString(), data.ID.ValueString())
+	if err != nil {
+		// Check if the record was deleted outside of Terraform
+		if errors.Is(err, porkbun.ErrNotFound) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read ACME challenge record: %s", err))
+		return
+	}
+
+	// A record whose content was changed outside of Terraform no longer
+	// answers the challenge, so it is recreated on the next apply.
+	if record.Content != acmeChallengeValue(data.KeyAuthorization.ValueString()) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ACMEChallengeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Every configurable attribute requires replacement, so only the
+	// timeouts block, or the way fqdn is written, can change here.
+	var data ACMEChallengeResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ACMEChallengeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ACMEChallengeResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, defaultACMEChallengeTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	tflog.Debug(ctx, "Deleting ACME challenge record", map[string]interface{}{
+		"id":     data.ID.ValueString(),
+		"domain": data.Domain.ValueString(),
+	})
+
+	// Deleting by ID leaves other challenges for the same name in place,
+	// such as the one for a wildcard certificate covering the same domain.
+	err := r.client.DeleteDNSRecord(ctx, data.Domain.ValueString(), data.ID.ValueString())
+	if err != nil && !errors.Is(err, porkbun.ErrNotFound) {
+		addClientError(&resp.Diagnostics, "Unable to delete ACME challenge record", err)
+		return
+	}
+}
+
+// acmeChallengeValue returns the TXT record content for a DNS-01 challenge:
+// the unpadded base64url SHA-256 digest of the key authorization.
+func acmeChallengeValue(keyAuthorization string) string {
+	digest := sha256.Sum256([]byte(keyAuthorization))
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
+
+// domainForName returns the domain in domains that name belongs to,
+// preferring the longest match so that a subdomain registered separately
+// wins over its parent.
+func domainForName(domains []porkbun.Domain, name string) (string, bool) {
+	var best string
+
+	for _, d := range domains {
+		domain := normalizeHostName(d.Domain)
+		if name != domain && !strings.HasSuffix(name, "."+domain) {
+			continue
+		}
+		if len(domain) > len(best) {
+			best = domain
+		}
+	}
+
+	return best, best != ""
+}
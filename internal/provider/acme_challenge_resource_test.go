@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+func TestACMEChallengeValue(t *testing.T) {
+	// A key authorization is the challenge token and the account key
+	// thumbprint, joined by a dot.
+	got := acmeChallengeValue("evaGxfADs6pSRb2LAv9IZf17Dt3juxGJ-PCt92wr-oA.nP1qzpXGymHBrUEepNY9HCsQk7K8KhOypzEt62jcerQ")
+	if want := "NGwKoXBgCT8JhEa0bK7AwfSqHyu_ZWeugV07fLGIVq0"; got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
+
+func TestDomainForName(t *testing.T) {
+	domains := []porkbun.Domain{
+		{Domain: "example.com"},
+		{Domain: "dev.example.com"},
+		{Domain: "example.co.uk"},
+	}
+
+	cases := []struct {
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{name: "example.com", want: "example.com", wantOK: true},
+		{name: "www.example.com", want: "example.com", wantOK: true},
+		{name: "api.dev.example.com", want: "dev.example.com", wantOK: true},
+		{name: "www.example.co.uk", want: "example.co.uk", wantOK: true},
+		{name: "notexample.com", wantOK: false},
+		{name: "example.org", wantOK: false},
+	}
+
+	for _, tc := range cases {
+		got, ok := domainForName(domains, tc.name)
+		if ok != tc.wantOK || got != tc.want {
+			t.Errorf("%s: expected (%q, %t), got (%q, %t)", tc.name, tc.want, tc.wantOK, got, ok)
+		}
+	}
+}
+
+func TestAccACMEChallengeResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccACMEChallengeResourceConfig("tf-acme-test"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_acme_challenge.test", "domain", testDomain),
+					resource.TestCheckResourceAttr("porkbun_acme_challenge.test", "name", "_acme-challenge.tf-acme-test"),
+					resource.TestCheckResourceAttr("porkbun_acme_challenge.wildcard", "name", "_acme-challenge.tf-acme-test"),
+					resource.TestCheckResourceAttr("porkbun_acme_challenge.test", "record_fqdn", "_acme-challenge.tf-acme-test."+testDomain),
+					resource.TestMatchResourceAttr("porkbun_acme_challenge.test", "value", regexp.MustCompile(`^[A-Za-z0-9_-]{43}$`)),
+					resource.TestCheckResourceAttrSet("porkbun_acme_challenge.test", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccACMEChallengeResourceConfig(name string) string {
+	return fmt.Sprintf(`
+resource "porkbun_acme_challenge" "test" {
+  fqdn              = "%[1]s.%[2]s"
+  key_authorization = "token-one.thumbprint"
+}
+
+resource "porkbun_acme_challenge" "wildcard" {
+  fqdn              = "*.%[1]s.%[2]s"
+  key_authorization = "token-two.thumbprint"
+}
+`, name, testDomain)
+}
@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &CAAFunction{}
+
+func NewCAAFunction() function.Function {
+	return &CAAFunction{}
+}
+
+// CAAFunction builds the content of a CAA record.
+type CAAFunction struct{}
+
+func (f *CAAFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "caa"
+}
+
+func (f *CAAFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Build CAA record content",
+		Description: "Returns the content of a CAA record, such as 0 issue \"letsencrypt.org\", " +
+			"for use with a porkbun_dns_record of type CAA.",
+		Parameters: []function.Parameter{
+			function.Int64Parameter{
+				Name:        "flags",
+				Description: "The flags byte. 128 marks the property as critical; use 0 otherwise.",
+				Validators: []function.Int64ParameterValidator{
+					int64validator.Between(0, 255),
+				},
+			},
+			function.StringParameter{
+				Name:        "tag",
+				Description: "The property tag, such as issue, issuewild or iodef.",
+				Validators: []function.StringParameterValidator{
+					caaTagValidator,
+				},
+			},
+			function.StringParameter{
+				Name:        "value",
+				Description: "The property value, such as letsencrypt.org.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *CAAFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var record caaRecord
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &record.Flags, &record.Tag, &record.Value))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, record.content()))
+}
@@ -1,341 +0,0 @@
-package provider
-
-import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"sync"
-	"time"
-)
-
-const (
-	defaultBaseURL = "https://api.porkbun.com/api/json/v3"
-)
-
-// Client is the Porkbun API client
-type Client struct {
-	baseURL      string
-	apiKey       string
-	secretAPIKey string
-	httpClient   *http.Client
-	mu           sync.Mutex // ensures only one API call at a time
-}
-
-// NewClient creates a new Porkbun API client
-func NewClient(apiKey, secretAPIKey string) *Client {
-	return &Client{
-		baseURL:      defaultBaseURL,
-		apiKey:       apiKey,
-		secretAPIKey: secretAPIKey,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-	}
-}
-
-// authRequest is the base request with authentication
-type authRequest struct {
-	SecretAPIKey string `json:"secretapikey"`
-	APIKey       string `json:"apikey"`
-}
-
-// APIResponse is the base response from the API
-type APIResponse struct {
-	Status  string `json:"status"`
-	Message string `json:"message,omitempty"`
-}
-
-// DNSRecord represents a DNS record
-type DNSRecord struct {
-	ID      string `json:"id"`
-	Name    string `json:"name"`
-	Type    string `json:"type"`
-	Content string `json:"content"`
-	TTL     string `json:"ttl"`
-	Prio    string `json:"prio"`
-	Notes   string `json:"notes"`
-}
-
-// CreateDNSRecordRequest is the request to create a DNS record
-type CreateDNSRecordRequest struct {
-	authRequest
-	Name    string `json:"name,omitempty"`
-	Type    string `json:"type"`
-	Content string `json:"content"`
-	TTL     string `json:"ttl,omitempty"`
-	Prio    string `json:"prio,omitempty"`
-	Notes   string `json:"notes,omitempty"`
-}
-
-// CreateDNSRecordResponse is the response from creating a DNS record
-type CreateDNSRecordResponse struct {
-	APIResponse
-	ID int64 `json:"id"`
-}
-
-// EditDNSRecordRequest is the request to edit a DNS record
-type EditDNSRecordRequest struct {
-	authRequest
-	Name    string `json:"name,omitempty"`
-	Type    string `json:"type"`
-	Content string `json:"content"`
-	TTL     string `json:"ttl,omitempty"`
-	Prio    string `json:"prio,omitempty"`
-	Notes   string `json:"notes,omitempty"`
-}
-
-// RetrieveDNSRecordsResponse is the response from retrieving DNS records
-type RetrieveDNSRecordsResponse struct {
-	APIResponse
-	Records []DNSRecord `json:"records"`
-}
-
-// doRequest performs an HTTP request to the Porkbun API
-// It uses a mutex to ensure only one request is made at a time
-// and retries on 503 errors with exponential backoff
-func (c *Client) doRequest(method, endpoint string, body interface{}) ([]byte, error) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	url := c.baseURL + endpoint
-
-	var jsonBody []byte
-	var err error
-	if body != nil {
-		jsonBody, err = json.Marshal(body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %w", err)
-		}
-	}
-
-	// Retry with exponential backoff for rate limiting (503 errors)
-	maxRetries := 5
-	baseDelay := 2 * time.Second
-
-	for attempt := 0; attempt < maxRetries; attempt++ {
-		var reqBody io.Reader
-		if jsonBody != nil {
-			reqBody = bytes.NewBuffer(jsonBody)
-		}
-
-		req, err := http.NewRequest(method, url, reqBody)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
-		}
-
-		req.Header.Set("Content-Type", "application/json")
-
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute request: %w", err)
-		}
-
-		respBody, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			return nil, fmt.Errorf("failed to read response body: %w", err)
-		}
-
-		// Retry on 503 Service Unavailable (rate limiting)
-		if resp.StatusCode == http.StatusServiceUnavailable {
-			if attempt < maxRetries-1 {
-				delay := baseDelay * time.Duration(1<<attempt) // exponential backoff
-				time.Sleep(delay)
-				continue
-			}
-		}
-
-		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(respBody))
-		}
-
-		return respBody, nil
-	}
-
-	return nil, fmt.Errorf("max retries exceeded")
-}
-
-// Ping tests the API connection
-func (c *Client) Ping() error {
-	req := authRequest{
-		SecretAPIKey: c.secretAPIKey,
-		APIKey:       c.apiKey,
-	}
-
-	respBody, err := c.doRequest("POST", "/ping", req)
-	if err != nil {
-		return err
-	}
-
-	var resp APIResponse
-	if err := json.Unmarshal(respBody, &resp); err != nil {
-		return fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	if resp.Status != "SUCCESS" {
-		return fmt.Errorf("API ping failed: %s", resp.Message)
-	}
-
-	return nil
-}
-
-// CreateDNSRecord creates a new DNS record
-func (c *Client) CreateDNSRecord(domain string, record CreateDNSRecordRequest) (string, error) {
-	record.SecretAPIKey = c.secretAPIKey
-	record.APIKey = c.apiKey
-
-	respBody, err := c.doRequest("POST", "/dns/create/"+domain, record)
-	if err != nil {
-		return "", err
-	}
-
-	var resp CreateDNSRecordResponse
-	if err := json.Unmarshal(respBody, &resp); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	if resp.Status != "SUCCESS" {
-		return "", fmt.Errorf("failed to create DNS record: %s", resp.Message)
-	}
-
-	return fmt.Sprintf("%d", resp.ID), nil
-}
-
-// GetDNSRecord retrieves a specific DNS record by ID
-func (c *Client) GetDNSRecord(domain, recordID string) (*DNSRecord, error) {
-	req := authRequest{
-		SecretAPIKey: c.secretAPIKey,
-		APIKey:       c.apiKey,
-	}
-
-	respBody, err := c.doRequest("POST", "/dns/retrieve/"+domain+"/"+recordID, req)
-	if err != nil {
-		return nil, err
-	}
-
-	var resp RetrieveDNSRecordsResponse
-	if err := json.Unmarshal(respBody, &resp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	if resp.Status != "SUCCESS" {
-		return nil, fmt.Errorf("failed to retrieve DNS record: %s", resp.Message)
-	}
-
-	if len(resp.Records) == 0 {
-		return nil, fmt.Errorf("DNS record not found")
-	}
-
-	return &resp.Records[0], nil
-}
-
-// EditDNSRecord updates an existing DNS record
-func (c *Client) EditDNSRecord(domain, recordID string, record EditDNSRecordRequest) error {
-	record.SecretAPIKey = c.secretAPIKey
-	record.APIKey = c.apiKey
-
-	respBody, err := c.doRequest("POST", "/dns/edit/"+domain+"/"+recordID, record)
-	if err != nil {
-		return err
-	}
-
-	var resp APIResponse
-	if err := json.Unmarshal(respBody, &resp); err != nil {
-		return fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	if resp.Status != "SUCCESS" {
-		return fmt.Errorf("failed to edit DNS record: %s", resp.Message)
-	}
-
-	return nil
-}
-
-// DeleteDNSRecord deletes a DNS record
-func (c *Client) DeleteDNSRecord(domain, recordID string) error {
-	req := authRequest{
-		SecretAPIKey: c.secretAPIKey,
-		APIKey:       c.apiKey,
-	}
-
-	respBody, err := c.doRequest("POST", "/dns/delete/"+domain+"/"+recordID, req)
-	if err != nil {
-		return err
-	}
-
-	var resp APIResponse
-	if err := json.Unmarshal(respBody, &resp); err != nil {
-		return fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	if resp.Status != "SUCCESS" {
-		return fmt.Errorf("failed to delete DNS record: %s", resp.Message)
-	}
-
-	return nil
-}
-
-// UpdateNameServersRequest is the request to update domain name servers
-type UpdateNameServersRequest struct {
-	authRequest
-	NS []string `json:"ns"`
-}
-
-// GetNameServersResponse is the response from retrieving name servers
-type GetNameServersResponse struct {
-	APIResponse
-	NS []string `json:"ns"`
-}
-
-// UpdateNameServers updates the name servers for a domain
-func (c *Client) UpdateNameServers(domain string, nameservers []string) error {
-	req := UpdateNameServersRequest{
-		authRequest: authRequest{
-			SecretAPIKey: c.secretAPIKey,
-			APIKey:       c.apiKey,
-		},
-		NS: nameservers,
-	}
-
-	respBody, err := c.doRequest("POST", "/domain/updateNs/"+domain, req)
-	if err != nil {
-		return err
-	}
-
-	var resp APIResponse
-	if err := json.Unmarshal(respBody, &resp); err != nil {
-		return fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	if resp.Status != "SUCCESS" {
-		return fmt.Errorf("failed to update name servers: %s", resp.Message)
-	}
-
-	return nil
-}
-
-// GetNameServers retrieves the name servers for a domain
-func (c *Client) GetNameServers(domain string) ([]string, error) {
-	req := authRequest{
-		SecretAPIKey: c.secretAPIKey,
-		APIKey:       c.apiKey,
-	}
-
-	respBody, err := c.doRequest("POST", "/domain/getNs/"+domain, req)
-	if err != nil {
-		return nil, err
-	}
-
-	var resp GetNameServersResponse
-	if err := json.Unmarshal(respBody, &resp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	if resp.Status != "SUCCESS" {
-		return nil, fmt.Errorf("failed to get name servers: %s", resp.Message)
-	}
-
-	return resp.NS, nil
-}
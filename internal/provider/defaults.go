@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Built-in values of porkbun_dns_record attributes that neither the resource
+// nor the provider's defaults block sets.
+const (
+	defaultRecordTTL   = "600"
+	defaultRecordPrio  = "0"
+	defaultRecordNotes = ""
+)
+
+// DefaultsModel describes the defaults block of the provider. The zero value
+// sets no defaults.
+type DefaultsModel struct {
+	Domain IDNStringValue `tfsdk:"domain"`
+	TTL    types.String   `tfsdk:"ttl"`
+	Notes  types.String   `tfsdk:"notes"`
+	Prio   types.Map      `tfsdk:"prio"`
+}
+
+// ttl returns the TTL for records that do not set one.
+func (d DefaultsModel) ttl() types.String {
+	if d.TTL.IsNull() {
+		return types.StringValue(defaultRecordTTL)
+	}
+	return d.TTL
+}
+
+// notes returns the notes for records that do not set them.
+func (d DefaultsModel) notes() types.String {
+	if d.Notes.IsNull() {
+		return types.StringValue(defaultRecordNotes)
+	}
+	return d.Notes
+}
+
+// prio returns the priority for records of recordType that do not set one.
+// It is unknown while the record type, or the prio map, is unknown and could
+// select a default other than the built-in one.
+func (d DefaultsModel) prio(recordType types.String) types.String {
+	if d.Prio.IsUnknown() {
+		return types.StringUnknown()
+	}
+
+	elements := d.Prio.Elements()
+	if recordType.IsUnknown() {
+		if len(elements) > 0 {
+			return types.StringUnknown()
+		}
+		return types.StringValue(defaultRecordPrio)
+	}
+
+	if v, ok := elements[recordType.ValueString()].(types.String); ok && !v.IsNull() {
+		return v
+	}
+	return types.StringValue(defaultRecordPrio)
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDefaultsModel(t *testing.T) {
+	var none DefaultsModel
+	if got := none.ttl(); got.ValueString() != "600" {
+		t.Errorf("expected the built-in TTL, got %s", got)
+	}
+	if got := none.notes(); got.ValueString() != "" {
+		t.Errorf("expected empty notes, got %s", got)
+	}
+	if got := none.prio(types.StringValue("MX")); got.ValueString() != "0" {
+		t.Errorf("expected the built-in priority, got %s", got)
+	}
+
+	defaults := DefaultsModel{
+		TTL:   types.StringValue("3600"),
+		Notes: types.StringValue("managed by terraform"),
+		Prio: types.MapValueMust(types.StringType, map[string]attr.Value{
+			"MX":  types.StringValue("10"),
+			"SRV": types.StringValue("20"),
+		}),
+	}
+	if got := defaults.ttl(); got.ValueString() != "3600" {
+		t.Errorf("expected TTL 3600, got %s", got)
+	}
+	if got := defaults.notes(); got.ValueString() != "managed by terraform" {
+		t.Errorf("unexpected notes %s", got)
+	}
+
+	cases := []struct {
+		recordType types.String
+		want       types.String
+	}{
+		{recordType: types.StringValue("MX"), want: types.StringValue("10")},
+		{recordType: types.StringValue("SRV"), want: types.StringValue("20")},
+		{recordType: types.StringValue("A"), want: types.StringValue("0")},
+		{recordType: types.StringUnknown(), want: types.StringUnknown()},
+	}
+	for _, tc := range cases {
+		if got := defaults.prio(tc.recordType); !got.Equal(tc.want) {
+			t.Errorf("%s: expected priority %s, got %s", tc.recordType, tc.want, got)
+		}
+	}
+
+	if got := none.prio(types.StringUnknown()); got.ValueString() != "0" {
+		t.Errorf("expected the built-in priority for an unknown type without defaults, got %s", got)
+	}
+}
@@ -0,0 +1,25 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// addClientError adds an error diagnostic for a failed client call. Calls cut
+// short by the operation's timeout are reported separately so they are not
+// mistaken for API failures.
+func addClientError(diags *diag.Diagnostics, summary string, err error) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		diags.AddError(
+			"Timeout Error",
+			fmt.Sprintf("%s: the operation did not finish before its timeout: %s\n\n"+
+				"Porkbun may be rate limiting requests. Increase the timeout in the resource's timeouts block to wait longer.", summary, err),
+		)
+		return
+	}
+
+	diags.AddError("Client Error", fmt.Sprintf("%s: %s", summary, err))
+}
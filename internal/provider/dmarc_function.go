@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// dmarcTags are the optional DMARC tags, in the order they are written. rua,
+// which has its own argument, goes before ruf.
+var dmarcTags = []string{"sp", "adkim", "aspf", "pct", "fo", "rf", "ri", "ruf"}
+
+// dmarcSizeLimit matches the size limit of a report address, such as the 10m
+// in mailto:dmarc@example.com!10m.
+var dmarcSizeLimit = regexp.MustCompile(`^[0-9]+[kmgt]?$`)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &DMARCFunction{}
+
+func NewDMARCFunction() function.Function {
+	return &DMARCFunction{}
+}
+
+// DMARCFunction builds the content of a DMARC TXT record.
+type DMARCFunction struct{}
+
+func (f *DMARCFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "dmarc"
+}
+
+func (f *DMARCFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Build DMARC record content",
+		Description: "Returns the content of a DMARC record, such as v=DMARC1; p=reject; rua=mailto:dmarc@example.com, " +
+			"for use with a porkbun_dns_record of type TXT named _dmarc.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "policy",
+				Description: "The policy for mail that fails DMARC: none, quarantine or reject.",
+				Validators: []function.StringParameterValidator{
+					stringvalidator.OneOf("none", "quarantine", "reject"),
+				},
+			},
+			function.ListParameter{
+				Name:           "rua",
+				Description:    "Addresses to send aggregate reports to, as email addresses or mailto: URIs. May be null or empty.",
+				ElementType:    types.StringType,
+				AllowNullValue: true,
+			},
+			function.MapParameter{
+				Name: "options",
+				Description: "Other tags, by name: sp, adkim, aspf, pct, fo, rf, ri and ruf (comma-separated addresses). " +
+					"May be null or empty.",
+				ElementType:    types.StringType,
+				AllowNullValue: true,
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *DMARCFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var policy string
+	var rua []string
+	var options map[string]string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &policy, &rua, &options))
+	if resp.Error != nil {
+		return
+	}
+
+	for name := range options {
+		if !isDMARCTag(name) {
+			resp.Error = function.NewArgumentFuncError(2, fmt.Sprintf("Invalid DMARC option %q: expected one of %s", name, strings.Join(dmarcTags, ", ")))
+			return
+		}
+	}
+
+	parts := []string{"v=DMARC1", "p=" + policy}
+
+	for _, name := range dmarcTags {
+		// Aggregate reports go before failure reports
+		if name == "ruf" && len(rua) > 0 {
+			uris, err := dmarcURIs(rua)
+			if err != nil {
+				resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("Invalid DMARC report address: %s", err))
+				return
+			}
+			parts = append(parts, "rua="+uris)
+		}
+
+		value, ok := options[name]
+		if !ok {
+			continue
+		}
+		value, err := dmarcTagValue(name, value)
+		if err != nil {
+			resp.Error = function.NewArgumentFuncError(2, fmt.Sprintf("Invalid DMARC option %s: %s", name, err))
+			return
+		}
+		parts = append(parts, name+"="+value)
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, strings.Join(parts, "; ")))
+}
+
+func isDMARCTag(name string) bool {
+	for _, tag := range dmarcTags {
+		if name == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// dmarcTagValue checks the value of an optional DMARC tag and returns it as
+// it is written in the record.
+func dmarcTagValue(name, value string) (string, error) {
+	switch name {
+	case "sp":
+		if value != "none" && value != "quarantine" && value != "reject" {
+			return "", fmt.Errorf("expected none, quarantine or reject, got %q", value)
+		}
+	case "adkim", "aspf":
+		if value != "r" && value != "s" {
+			return "", fmt.Errorf("expected r (relaxed) or s (strict), got %q", value)
+		}
+	case "pct":
+		if n, err := strconv.Atoi(value); err != nil || n < 0 || n > 100 {
+			return "", fmt.Errorf("expected a percentage from 0 to 100, got %q", value)
+		}
+	case "fo":
+		for _, option := range strings.Split(value, ":") {
+			if option != "0" && option != "1" && option != "d" && option != "s" {
+				return "", fmt.Errorf("expected colon-separated options 0, 1, d and s, got %q", value)
+			}
+		}
+	case "rf":
+		if value != "afrf" {
+			return "", fmt.Errorf("expected afrf, got %q", value)
+		}
+	case "ri":
+		if _, err := strconv.ParseUint(value, 10, 32); err != nil {
+			return "", fmt.Errorf("expected a number of seconds, got %q", value)
+		}
+	case "ruf":
+		return dmarcURIs(strings.Split(value, ","))
+	}
+	return value, nil
+}
+
+// dmarcURIs returns report addresses as a comma-separated list of mailto:
+// URIs. Addresses may be written with or without mailto:, and may end in a
+// size limit such as !10m.
+func dmarcURIs(addresses []string) (string, error) {
+	uris := make([]string, 0, len(addresses))
+
+	for _, address := range addresses {
+		uri := strings.TrimPrefix(strings.TrimSpace(address), "mailto:")
+		addr, limit, hasLimit := strings.Cut(uri, "!")
+
+		if _, err := mail.ParseAddress(addr); err != nil || strings.ContainsAny(addr, ",;<> ") {
+			return "", fmt.Errorf("%q is not an email address", address)
+		}
+		if hasLimit && !dmarcSizeLimit.MatchString(limit) {
+			return "", fmt.Errorf("%q has an invalid size limit", address)
+		}
+
+		uris = append(uris, "mailto:"+uri)
+	}
+
+	return strings.Join(uris, ","), nil
+}
@@ -0,0 +1,429 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/miekg/dns"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+// dnsQuerier sends the DNS queries used to check name servers before and
+// after changes are made through the API. Name server addresses are looked up
+// through a recursive resolver; the checks themselves are sent directly to
+// the name servers, without recursion.
+type dnsQuerier struct {
+	// resolver is the host:port of the resolver used to look up name server
+	// addresses. Empty means the system resolver.
+	resolver string
+
+	// port is the port name servers are queried on, unless given as
+	// host:port.
+	port string
+
+	// timeout bounds each individual query.
+	timeout time.Duration
+}
+
+// newDNSQuerier returns a querier looking up addresses through resolver and
+// querying name servers on port. Empty values mean the system resolver and
+// port 53.
+func newDNSQuerier(resolver, port string) *dnsQuerier {
+	if port == "" {
+		port = "53"
+	}
+	return &dnsQuerier{
+		resolver: resolver,
+		port:     port,
+		timeout:  5 * time.Second,
+	}
+}
+
+// netResolver returns the recursive resolver used for lookups.
+func (q *dnsQuerier) netResolver() *net.Resolver {
+	if q.resolver == "" {
+		return net.DefaultResolver
+	}
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, q.resolver)
+		},
+	}
+}
+
+// lookupHost returns the addresses of a name server. IPv4 addresses are
+// preferred, since IPv6 connectivity is often missing where Terraform runs.
+func (q *dnsQuerier) lookupHost(ctx context.Context, host string) ([]string, error) {
+	if net.ParseIP(host) != nil {
+		return []string{host}, nil
+	}
+
+	addrs, err := q.netResolver().LookupHost(ctx, strings.TrimSuffix(host, "."))
+	if err != nil {
+		return nil, err
+	}
+
+	var v4, v6 []string
+	for _, addr := range addrs {
+		if ip := net.ParseIP(addr); ip != nil && ip.To4() != nil {
+			v4 = append(v4, addr)
+		} else {
+			v6 = append(v6, addr)
+		}
+	}
+	if len(v4) > 0 {
+		return v4, nil
+	}
+	return v6, nil
+}
+
+// exchange sends a non-recursive query for name and qtype to the name server
+// at addr, retrying over TCP if the UDP response is truncated.
+func (q *dnsQuerier) exchange(ctx context.Context, addr, name string, qtype uint16) (*dns.Msg, error) {
+	return q.exchangeAt(ctx, net.JoinHostPort(addr, q.port), name, qtype)
+}
+
+// exchangeAt is exchange for a name server given as host:port.
+func (q *dnsQuerier) exchangeAt(ctx context.Context, server, name string, qtype uint16) (*dns.Msg, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+	msg.RecursionDesired = false
+
+	client := &dns.Client{Timeout: q.timeout}
+
+	resp, _, err := client.ExchangeContext(ctx, msg, server)
+	if err == nil && resp.Truncated {
+		client.Net = "tcp"
+		resp, _, err = client.ExchangeContext(ctx, msg, server)
+	}
+	return resp, err
+}
+
+// checkAuthoritativeAnswer verifies that resp is an authoritative answer
+// containing at least one record of qtype for name.
+func checkAuthoritativeAnswer(resp *dns.Msg, name string, qtype uint16) error {
+	if resp.Rcode != dns.RcodeSuccess {
+		return fmt.Errorf("answered %s", dns.RcodeToString[resp.Rcode])
+	}
+	if !resp.Authoritative {
+		return fmt.Errorf("is not authoritative for %s (lame delegation)", strings.TrimSuffix(name, "."))
+	}
+	for _, rr := range resp.Answer {
+		if rr.Header().Rrtype == qtype && strings.EqualFold(rr.Header().Name, dns.Fqdn(name)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("returned no %s record for %s", dns.TypeToString[qtype], strings.TrimSuffix(name, "."))
+}
+
+// preflightNameServers checks that each of nameservers serves the zone for
+// domain, answering authoritatively for its SOA and NS records. It returns
+// one message per name server that fails the check.
+func (q *dnsQuerier) preflightNameServers(ctx context.Context, domain string, nameservers []string) []string {
+	var problems []string
+
+	for _, ns := range nameservers {
+		if err := q.checkNameServer(ctx, domain, ns); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", ns, err))
+		}
+	}
+
+	return problems
+}
+
+// checkNameServer checks a single name server for preflightNameServers.
+func (q *dnsQuerier) checkNameServer(ctx context.Context, domain, ns string) error {
+	addrs, err := q.lookupHost(ctx, ns)
+	if err != nil {
+		return fmt.Errorf("unable to look up address: %w", err)
+	}
+
+	for _, addr := range addrs {
+		for _, qtype := range []uint16{dns.TypeSOA, dns.TypeNS} {
+			resp, err := q.exchange(ctx, addr, domain, qtype)
+			if err != nil {
+				return fmt.Errorf("no answer from %s: %w", addr, err)
+			}
+			if err := checkAuthoritativeAnswer(resp, domain, qtype); err != nil {
+				return fmt.Errorf("%s %w", addr, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// parentNameServers returns the zone that delegates domain, such as the
+// TLD, together with the name servers that are authoritative for it.
+func (q *dnsQuerier) parentNameServers(ctx context.Context, domain string) (string, []string, error) {
+	labels := dns.SplitDomainName(domain)
+
+	for i := 1; i < len(labels); i++ {
+		zone := strings.Join(labels[i:], ".")
+
+		records, err := q.netResolver().LookupNS(ctx, zone)
+		if err != nil || len(records) == 0 {
+			continue
+		}
+
+		servers := make([]string, len(records))
+		for j, record := range records {
+			servers[j] = record.Host
+		}
+		return zone, servers, nil
+	}
+
+	return "", nil, fmt.Errorf("unable to find the parent zone of %s", domain)
+}
+
+// delegation returns the name servers that the parent zone server at addr
+// delegates domain to, lower-cased, without the trailing dot and sorted.
+func (q *dnsQuerier) delegation(ctx context.Context, addr, domain string) ([]string, error) {
+	resp, err := q.exchange(ctx, addr, domain, dns.TypeNS)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Rcode != dns.RcodeSuccess {
+		return nil, fmt.Errorf("answered %s", dns.RcodeToString[resp.Rcode])
+	}
+
+	// Parent zone servers answer with a referral, which carries the NS
+	// records in the authority section rather than the answer section.
+	var nameservers []string
+	for _, rr := range append(resp.Answer, resp.Ns...) {
+		if ns, ok := rr.(*dns.NS); ok && strings.EqualFold(ns.Hdr.Name, dns.Fqdn(domain)) {
+			nameservers = append(nameservers, normalizeHostName(ns.Ns))
+		}
+	}
+	sort.Strings(nameservers)
+
+	return nameservers, nil
+}
+
+// waitForDelegation polls every name server of the parent zone until all of
+// them delegate domain to exactly nameservers, checking again after each
+// interval. It gives up when ctx is done.
+func (q *dnsQuerier) waitForDelegation(ctx context.Context, domain string, nameservers []string, interval time.Duration) error {
+	want := make([]string, len(nameservers))
+	for i, ns := range nameservers {
+		want[i] = normalizeHostName(ns)
+	}
+	sort.Strings(want)
+
+	zone, parents, err := q.parentNameServers(ctx, domain)
+	if err != nil {
+		return err
+	}
+
+	fields := map[string]interface{}{
+		"domain": domain,
+		"zone":   zone,
+	}
+	pending, err := poll(ctx, interval, parents, func(parent string) bool {
+		return q.delegatesTo(ctx, parent, domain, want)
+	}, "Waiting for delegation to propagate", fields)
+	if err != nil {
+		return fmt.Errorf("%s servers still hand out the old delegation (%s): %w",
+			zone, strings.Join(pending, ", "), err)
+	}
+
+	tflog.Info(ctx, "Delegation has propagated", fields)
+	return nil
+}
+
+// delegatesTo reports whether the parent zone server parent delegates
+// domain to want. Failures to reach the server count as not yet delegated,
+// so they are retried on the next poll.
+func (q *dnsQuerier) delegatesTo(ctx context.Context, parent, domain string, want []string) bool {
+	addrs, err := q.lookupHost(ctx, parent)
+	if err != nil || len(addrs) == 0 {
+		tflog.Debug(ctx, "Unable to look up parent zone server", map[string]interface{}{
+			"server": parent,
+			"error":  fmt.Sprint(err),
+		})
+		return false
+	}
+
+	got, err := q.delegation(ctx, addrs[0], domain)
+	if err != nil {
+		tflog.Debug(ctx, "Unable to query parent zone server", map[string]interface{}{
+			"server": parent,
+			"error":  err.Error(),
+		})
+		return false
+	}
+
+	return strings.Join(got, " ") == strings.Join(want, " ")
+}
+
+// normalizeHostName lower-cases a host name and removes the trailing dot, so
+// that host names from DNS and the API compare equal.
+func normalizeHostName(ns string) string {
+	return strings.ToLower(strings.TrimSuffix(ns, "."))
+}
+
+// poll checks each of servers every interval until check passes for all of
+// them, logging progress with message and fields in between. When ctx is
+// done it returns the servers still pending together with the context's
+// error.
+func poll(ctx context.Context, interval time.Duration, servers []string, check func(server string) bool, message string, fields map[string]interface{}) ([]string, error) {
+	for {
+		var pending []string
+		for _, server := range servers {
+			if !check(server) {
+				pending = append(pending, strings.TrimSuffix(server, "."))
+			}
+		}
+
+		if len(pending) == 0 {
+			return nil, nil
+		}
+
+		progress := map[string]interface{}{
+			"updated": len(servers) - len(pending),
+			"total":   len(servers),
+			"pending": pending,
+		}
+		for k, v := range fields {
+			progress[k] = v
+		}
+		tflog.Info(ctx, message, progress)
+
+		select {
+		case <-ctx.Done():
+			return pending, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// waitForRecord polls every one of nameservers until each of them serves a
+// record of recordType for fqdn with content (and prio, where the type has
+// one), checking again after each interval. Name servers may be given as
+// host:port. It gives up when ctx is done.
+func (q *dnsQuerier) waitForRecord(ctx context.Context, nameservers []string, fqdn, recordType, content, prio string, interval time.Duration) error {
+	fields := map[string]interface{}{
+		"name": fqdn,
+		"type": recordType,
+	}
+	pending, err := poll(ctx, interval, nameservers, func(ns string) bool {
+		return q.servesRecord(ctx, ns, fqdn, recordType, content, prio)
+	}, "Waiting for DNS record to propagate", fields)
+	if err != nil {
+		return fmt.Errorf("%s %s record is not yet served by %s: %w",
+			fqdn, recordType, strings.Join(pending, ", "), err)
+	}
+
+	tflog.Info(ctx, "DNS record has propagated", fields)
+	return nil
+}
+
+// servesRecord reports whether every address of the name server ns answers
+// authoritatively with the record. Failures count as not yet served, so
+// they are retried on the next poll.
+func (q *dnsQuerier) servesRecord(ctx context.Context, ns, fqdn, recordType, content, prio string) bool {
+	host, port := ns, q.port
+	if h, p, err := net.SplitHostPort(ns); err == nil {
+		host, port = h, p
+	}
+
+	// ALIAS records are flattened by the name server and served as A
+	// records, whose addresses cannot be known here.
+	qtype, ok := dns.StringToType[recordType]
+	if recordType == "ALIAS" {
+		qtype, ok = dns.TypeA, true
+	}
+	if !ok {
+		return false
+	}
+
+	addrs, err := q.lookupHost(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		tflog.Debug(ctx, "Unable to look up name server", map[string]interface{}{
+			"server": ns,
+			"error":  fmt.Sprint(err),
+		})
+		return false
+	}
+
+	for _, addr := range addrs {
+		resp, err := q.exchangeAt(ctx, net.JoinHostPort(addr, port), fqdn, qtype)
+		if err == nil {
+			err = checkAuthoritativeAnswer(resp, fqdn, qtype)
+		}
+		if err != nil {
+			tflog.Debug(ctx, "Name server does not serve the record yet", map[string]interface{}{
+				"server":  ns,
+				"address": addr,
+				"error":   err.Error(),
+			})
+			return false
+		}
+
+		found := recordType == "ALIAS"
+		for _, rr := range resp.Answer {
+			if recordMatches(rr, content, prio) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// recordMatches reports whether rr carries the record content, as the
+// Porkbun API represents it, and prio where the record type has one.
+func recordMatches(rr dns.RR, content, prio string) bool {
+	switch rr := rr.(type) {
+	case *dns.A:
+		return rr.A.Equal(net.ParseIP(content))
+	case *dns.AAAA:
+		return rr.AAAA.Equal(net.ParseIP(content))
+	case *dns.CNAME:
+		return normalizeHostName(rr.Target) == normalizeHostName(content)
+	case *dns.NS:
+		return normalizeHostName(rr.Ns) == normalizeHostName(content)
+	case *dns.MX:
+		return normalizeHostName(rr.Mx) == normalizeHostName(content) &&
+			(prio == "" || strconv.Itoa(int(rr.Preference)) == prio)
+	case *dns.TXT:
+		return strings.Join(rr.Txt, "") == porkbun.DecodeTXTContent(content)
+	case *dns.SRV:
+		// Porkbun keeps the SRV priority in prio and the rest in content.
+		return sameRData(strings.TrimPrefix(rr.String(), rr.Hdr.String()), prio+" "+content)
+	default:
+		return sameRData(strings.TrimPrefix(rr.String(), rr.Header().String()), content)
+	}
+}
+
+// sameRData compares record data in zone file presentation format field by
+// field, ignoring case, quotes and trailing dots.
+func sameRData(a, b string) bool {
+	fa, fb := strings.Fields(a), strings.Fields(b)
+	if len(fa) != len(fb) {
+		return false
+	}
+
+	for i := range fa {
+		x := strings.TrimSuffix(strings.Trim(fa[i], `"`), ".")
+		y := strings.TrimSuffix(strings.Trim(fb[i], `"`), ".")
+		if !strings.EqualFold(x, y) {
+			return false
+		}
+	}
+
+	return true
+}
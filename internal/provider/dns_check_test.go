@@ -0,0 +1,326 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/miekg/dns"
+)
+
+// startTestDNSServer serves handler over UDP on a free local port and
+// returns the port.
+func startTestDNSServer(t *testing.T, handler dns.HandlerFunc) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %s", err)
+	}
+
+	started := make(chan struct{})
+	server := &dns.Server{PacketConn: conn, Handler: handler, NotifyStartedFunc: func() { close(started) }}
+	go func() {
+		_ = server.ActivateAndServe()
+	}()
+	<-started
+	t.Cleanup(func() { _ = server.Shutdown() })
+
+	_, port, _ := net.SplitHostPort(conn.LocalAddr().String())
+	return port
+}
+
+// testDNSQuerier returns the querier the provider configures when both the
+// resolver and the name servers are the test DNS server on port.
+func testDNSQuerier(t *testing.T, port string) *dnsQuerier {
+	t.Helper()
+
+	env := map[string]string{
+		"PORKBUN_DNS_RESOLVER":   net.JoinHostPort("127.0.0.1", port),
+		"PORKBUN_DNS_QUERY_PORT": port,
+	}
+	q, err := configureDNSQuerier(PorkbunProviderModel{}, func(key string) string { return env[key] })
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q
+}
+
+// testZoneHandler answers for example.com the way a name server hosting the
+// zone would, and resolves ns1.example.net to 127.0.0.1 like a recursive
+// resolver. When authoritative is false it answers without the AA flag, like
+// a server that has not been set up with the zone.
+func testZoneHandler(authoritative bool) dns.HandlerFunc {
+	return func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+
+		q := req.Question[0]
+		switch {
+		case q.Name == "ns1.example.net." && q.Qtype == dns.TypeA:
+			rr, _ := dns.NewRR("ns1.example.net. 300 IN A 127.0.0.1")
+			resp.Answer = append(resp.Answer, rr)
+		case q.Name == "example.com." && authoritative:
+			resp.Authoritative = true
+			switch q.Qtype {
+			case dns.TypeSOA:
+				rr, _ := dns.NewRR("example.com. 300 IN SOA ns1.example.net. hostmaster.example.com. 1 7200 3600 1209600 300")
+				resp.Answer = append(resp.Answer, rr)
+			case dns.TypeNS:
+				rr, _ := dns.NewRR("example.com. 300 IN NS ns1.example.net.")
+				resp.Answer = append(resp.Answer, rr)
+			}
+		case q.Name == "example.com.":
+			resp.Rcode = dns.RcodeRefused
+		}
+
+		_ = w.WriteMsg(resp)
+	}
+}
+
+func TestDNSQuerier_PreflightNameServers(t *testing.T) {
+	testCases := map[string]struct {
+		authoritative bool
+		nameservers   []string
+		wantProblem   string
+	}{
+		"serves zone": {
+			authoritative: true,
+			nameservers:   []string{"ns1.example.net", "127.0.0.1"},
+		},
+		"lame delegation": {
+			authoritative: false,
+			nameservers:   []string{"ns1.example.net"},
+			wantProblem:   "ns1.example.net: 127.0.0.1 answered REFUSED",
+		},
+		"unknown name server": {
+			authoritative: true,
+			nameservers:   []string{"ns2.example.net"},
+			wantProblem:   "ns2.example.net: unable to look up address",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			port := startTestDNSServer(t, testZoneHandler(tc.authoritative))
+
+			q := testDNSQuerier(t, port)
+
+			problems := q.preflightNameServers(context.Background(), "example.com", tc.nameservers)
+
+			if tc.wantProblem == "" {
+				if len(problems) != 0 {
+					t.Fatalf("expected no problems, got: %v", problems)
+				}
+				return
+			}
+			if len(problems) != 1 || !strings.HasPrefix(problems[0], tc.wantProblem) {
+				t.Fatalf("expected a problem starting with %q, got: %v", tc.wantProblem, problems)
+			}
+		})
+	}
+}
+
+func TestConfigureDNSQuerier(t *testing.T) {
+	env := map[string]string{
+		"PORKBUN_DNS_RESOLVER":   "192.0.2.1:53",
+		"PORKBUN_DNS_QUERY_PORT": "5353",
+	}
+	getenv := func(key string) string { return env[key] }
+
+	q, err := configureDNSQuerier(PorkbunProviderModel{}, func(string) string { return "" })
+	if err != nil || q.resolver != "" || q.port != "53" {
+		t.Fatalf("expected the system resolver and port 53, got %+v, %v", q, err)
+	}
+
+	q, err = configureDNSQuerier(PorkbunProviderModel{}, getenv)
+	if err != nil || q.resolver != "192.0.2.1:53" || q.port != "5353" {
+		t.Fatalf("expected the settings from the environment, got %+v, %v", q, err)
+	}
+
+	config := PorkbunProviderModel{
+		DNSResolver:  types.StringValue("192.0.2.2:53"),
+		DNSQueryPort: types.Int64Value(8053),
+	}
+	q, err = configureDNSQuerier(config, getenv)
+	if err != nil || q.resolver != "192.0.2.2:53" || q.port != "8053" {
+		t.Fatalf("expected the settings from the configuration, got %+v, %v", q, err)
+	}
+
+	env["PORKBUN_DNS_QUERY_PORT"] = "dns"
+	if _, err := configureDNSQuerier(PorkbunProviderModel{}, getenv); err == nil {
+		t.Fatal("expected an error for an invalid port")
+	}
+}
+
+func TestCheckAuthoritativeAnswer(t *testing.T) {
+	req := new(dns.Msg)
+	req.SetQuestion("example.com.", dns.TypeSOA)
+
+	resp := new(dns.Msg)
+	resp.SetReply(req)
+
+	if err := checkAuthoritativeAnswer(resp, "example.com", dns.TypeSOA); err == nil || !strings.Contains(err.Error(), "lame delegation") {
+		t.Fatalf("expected a lame delegation error, got: %v", err)
+	}
+
+	resp.Authoritative = true
+	if err := checkAuthoritativeAnswer(resp, "example.com", dns.TypeSOA); err == nil || !strings.Contains(err.Error(), "no SOA record") {
+		t.Fatalf("expected a missing record error, got: %v", err)
+	}
+
+	rr, _ := dns.NewRR("example.com. 300 IN SOA ns1.example.net. hostmaster.example.com. 1 7200 3600 1209600 300")
+	resp.Answer = append(resp.Answer, rr)
+	if err := checkAuthoritativeAnswer(resp, "example.com", dns.TypeSOA); err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+}
+
+// testParentHandler answers like the servers of the com zone, referring
+// example.com to the name servers returned by delegation, and resolves the
+// com zone's own name server to 127.0.0.1 like a recursive resolver.
+func testParentHandler(delegation func() []string) dns.HandlerFunc {
+	return func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+
+		q := req.Question[0]
+		switch {
+		case q.Name == "com." && q.Qtype == dns.TypeNS:
+			rr, _ := dns.NewRR("com. 300 IN NS a.gtld.example.net.")
+			resp.Answer = append(resp.Answer, rr)
+		case q.Name == "a.gtld.example.net." && q.Qtype == dns.TypeA:
+			rr, _ := dns.NewRR("a.gtld.example.net. 300 IN A 127.0.0.1")
+			resp.Answer = append(resp.Answer, rr)
+		case q.Name == "example.com." && q.Qtype == dns.TypeNS:
+			for _, ns := range delegation() {
+				rr, _ := dns.NewRR("example.com. 300 IN NS " + ns)
+				resp.Ns = append(resp.Ns, rr)
+			}
+		}
+
+		_ = w.WriteMsg(resp)
+	}
+}
+
+func TestDNSQuerier_WaitForDelegation(t *testing.T) {
+	var queries atomic.Int32
+
+	port := startTestDNSServer(t, testParentHandler(func() []string {
+		if queries.Add(1) < 3 {
+			return []string{"curitiba.ns.porkbun.com.", "fortaleza.ns.porkbun.com."}
+		}
+		return []string{"NS1.example.net.", "ns2.example.net."}
+	}))
+
+	q := testDNSQuerier(t, port)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := q.waitForDelegation(ctx, "example.com", []string{"ns2.example.net", "ns1.example.net"}, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("expected the delegation to propagate, got error: %s", err)
+	}
+	if got := queries.Load(); got != 3 {
+		t.Fatalf("expected 3 delegation checks, got %d", got)
+	}
+}
+
+func TestDNSQuerier_WaitForDelegationTimeout(t *testing.T) {
+	port := startTestDNSServer(t, testParentHandler(func() []string {
+		return []string{"curitiba.ns.porkbun.com."}
+	}))
+
+	q := testDNSQuerier(t, port)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err := q.waitForDelegation(ctx, "example.com", []string{"ns1.example.net"}, 10*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline exceeded error, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "a.gtld.example.net") {
+		t.Fatalf("expected the error to name the pending server, got: %s", err)
+	}
+}
+
+func TestDNSQuerier_WaitForRecord(t *testing.T) {
+	var queries atomic.Int32
+
+	port := startTestDNSServer(t, func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		resp.Authoritative = true
+
+		q := req.Question[0]
+		if q.Name == "_acme-challenge.example.com." && q.Qtype == dns.TypeTXT {
+			rr, _ := dns.NewRR(`_acme-challenge.example.com. 600 IN TXT "old"`)
+			resp.Answer = append(resp.Answer, rr)
+			if queries.Add(1) >= 3 {
+				rr, _ := dns.NewRR(`_acme-challenge.example.com. 600 IN TXT "new"`)
+				resp.Answer = append(resp.Answer, rr)
+			}
+		}
+
+		_ = w.WriteMsg(resp)
+	})
+
+	q := newDNSQuerier("", "")
+	server := net.JoinHostPort("127.0.0.1", port)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := q.waitForRecord(ctx, []string{server}, "_acme-challenge.example.com", "TXT", "new", "0", 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("expected the record to propagate, got error: %s", err)
+	}
+	if got := queries.Load(); got != 3 {
+		t.Fatalf("expected 3 checks, got %d", got)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err = q.waitForRecord(ctx, []string{server}, "_acme-challenge.example.com", "TXT", "missing", "0", 10*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline exceeded error, got: %v", err)
+	}
+}
+
+func TestRecordMatches(t *testing.T) {
+	testCases := []struct {
+		rr      string
+		content string
+		prio    string
+		want    bool
+	}{
+		{rr: "www.example.com. 600 IN A 192.0.2.1", content: "192.0.2.1", want: true},
+		{rr: "www.example.com. 600 IN A 192.0.2.1", content: "192.0.2.2", want: false},
+		{rr: "www.example.com. 600 IN AAAA 2001:db8::1", content: "2001:DB8:0::1", want: true},
+		{rr: "www.example.com. 600 IN CNAME Target.Example.net.", content: "target.example.net", want: true},
+		{rr: "example.com. 600 IN MX 10 mail.example.com.", content: "mail.example.com", prio: "10", want: true},
+		{rr: "example.com. 600 IN MX 10 mail.example.com.", content: "mail.example.com", prio: "20", want: false},
+		{rr: `example.com. 600 IN TXT "v=spf1 " "-all"`, content: "v=spf1 -all", want: true},
+		{rr: "_sip._tcp.example.com. 600 IN SRV 10 5 5060 sip.example.com.", content: "5 5060 sip.example.com", prio: "10", want: true},
+		{rr: `example.com. 600 IN CAA 0 issue "letsencrypt.org"`, content: `0 issue "letsencrypt.org"`, want: true},
+		{rr: `example.com. 600 IN CAA 0 issue "letsencrypt.org"`, content: `0 issue "pki.goog"`, want: false},
+	}
+
+	for _, tc := range testCases {
+		rr, err := dns.NewRR(tc.rr)
+		if err != nil {
+			t.Fatalf("parse %q: %s", tc.rr, err)
+		}
+		if got := recordMatches(rr, tc.content, tc.prio); got != tc.want {
+			t.Errorf("recordMatches(%q, %q, %q) = %t, want %t", tc.rr, tc.content, tc.prio, got, tc.want)
+		}
+	}
+}
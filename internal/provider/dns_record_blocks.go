@@ -0,0 +1,399 @@
+package provider
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+// contentBlockTypes maps each structured content block of porkbun_dns_record
+// to the record types it can be used with.
+var contentBlockTypes = map[string][]string{
+	"srv":  {"SRV"},
+	"caa":  {"CAA"},
+	"tlsa": {"TLSA"},
+	"svcb": {"HTTPS", "SVCB"},
+}
+
+// caaTagValidator checks CAA property tags, which RFC 8659 limits to 1 to 15
+// letters and digits. It validates both the caa block and the caa function.
+var caaTagValidator = stringvalidator.RegexMatches(regexp.MustCompile(`^[A-Za-z0-9]{1,15}$`), "must be 1 to 15 letters and digits")
+
+// SRVModel describes the srv block.
+type SRVModel struct {
+	Service types.String `tfsdk:"service"`
+	Proto   types.String `tfsdk:"proto"`
+	Weight  types.Int64  `tfsdk:"weight"`
+	Port    types.Int64  `tfsdk:"port"`
+	Target  types.String `tfsdk:"target"`
+}
+
+// CAAModel describes the caa block.
+type CAAModel struct {
+	Flags types.Int64  `tfsdk:"flags"`
+	Tag   types.String `tfsdk:"tag"`
+	Value types.String `tfsdk:"value"`
+}
+
+// TLSAModel describes the tlsa block.
+type TLSAModel struct {
+	Usage        types.Int64  `tfsdk:"usage"`
+	Selector     types.Int64  `tfsdk:"selector"`
+	MatchingType types.Int64  `tfsdk:"matching_type"`
+	Data         types.String `tfsdk:"data"`
+}
+
+// SVCBModel describes the svcb block.
+type SVCBModel struct {
+	Priority types.Int64  `tfsdk:"priority"`
+	Target   types.String `tfsdk:"target"`
+	Params   types.Map    `tfsdk:"params"`
+}
+
+// dnsRecordContentBlocks returns the schema of the structured content
+// blocks of porkbun_dns_record.
+func dnsRecordContentBlocks() map[string]schema.Block {
+	return map[string]schema.Block{
+		"srv": schema.SingleNestedBlock{
+			Description: "Structured content for SRV records, used instead of content. The record is named " +
+				"_service._proto under name, and its priority is taken from prio.",
+			Attributes: map[string]schema.Attribute{
+				"service": schema.StringAttribute{
+					Description: "The service name, such as sip. The leading underscore is optional.",
+					Required:    true,
+				},
+				"proto": schema.StringAttribute{
+					Description: "The protocol, such as tcp or udp. The leading underscore is optional.",
+					Required:    true,
+				},
+				"weight": schema.Int64Attribute{
+					Description: "The relative weight of records with the same priority.",
+					Required:    true,
+					Validators: []validator.Int64{
+						int64validator.Between(0, 65535),
+					},
+				},
+				"port": schema.Int64Attribute{
+					Description: "The port the service listens on.",
+					Required:    true,
+					Validators: []validator.Int64{
+						int64validator.Between(0, 65535),
+					},
+				},
+				"target": schema.StringAttribute{
+					Description: "The host name providing the service, or . if the service is not available.",
+					Required:    true,
+					Validators: []validator.String{
+						validTarget(),
+					},
+				},
+			},
+		},
+		"caa": schema.SingleNestedBlock{
+			Description: "Structured content for CAA records, used instead of content.",
+			Attributes: map[string]schema.Attribute{
+				"flags": schema.Int64Attribute{
+					Description: "The flags byte. 128 marks the property as critical. Defaults to 0.",
+					Optional:    true,
+					Validators: []validator.Int64{
+						int64validator.Between(0, 255),
+					},
+				},
+				"tag": schema.StringAttribute{
+					Description: "The property tag, such as issue, issuewild or iodef.",
+					Required:    true,
+					Validators: []validator.String{
+						caaTagValidator,
+					},
+				},
+				"value": schema.StringAttribute{
+					Description: "The property value, such as letsencrypt.org.",
+					Required:    true,
+				},
+			},
+		},
+		"tlsa": schema.SingleNestedBlock{
+			Description: "Structured content for TLSA records, used instead of content.",
+			Attributes: map[string]schema.Attribute{
+				"usage": schema.Int64Attribute{
+					Description: "The certificate usage, from 0 (PKIX-TA) to 3 (DANE-EE).",
+					Required:    true,
+					Validators: []validator.Int64{
+						int64validator.Between(0, 255),
+					},
+				},
+				"selector": schema.Int64Attribute{
+					Description: "The selector: 0 for the full certificate, 1 for the public key.",
+					Required:    true,
+					Validators: []validator.Int64{
+						int64validator.Between(0, 255),
+					},
+				},
+				"matching_type": schema.Int64Attribute{
+					Description: "The matching type: 0 for exact match, 1 for SHA-256, 2 for SHA-512.",
+					Required:    true,
+					Validators: []validator.Int64{
+						int64validator.Between(0, 255),
+					},
+				},
+				"data": schema.StringAttribute{
+					Description: "The certificate association data, hex encoded.",
+					Required:    true,
+				},
+			},
+		},
+		"svcb": schema.SingleNestedBlock{
+			Description: "Structured content for HTTPS and SVCB records, used instead of content.",
+			Attributes: map[string]schema.Attribute{
+				"priority": schema.Int64Attribute{
+					Description: "The priority. 0 makes the record an alias to target.",
+					Required:    true,
+					Validators: []validator.Int64{
+						int64validator.Between(0, 65535),
+					},
+				},
+				"target": schema.StringAttribute{
+					Description: "The target host name, or . for the record's own name.",
+					Required:    true,
+					Validators: []validator.String{
+						validTarget(),
+					},
+				},
+				"params": schema.MapAttribute{
+					Description: "Service parameters, such as alpn = \"h2,h3\". Use an empty value for parameters that take none.",
+					Optional:    true,
+					ElementType: types.StringType,
+				},
+			},
+		},
+	}
+}
+
+// contentBlock returns the name of the structured content block set in the
+// model, if any.
+func (m DNSRecordResourceModel) contentBlock() string {
+	switch {
+	case m.SRV != nil:
+		return "srv"
+	case m.CAA != nil:
+		return "caa"
+	case m.TLSA != nil:
+		return "tlsa"
+	case m.SVCB != nil:
+		return "svcb"
+	}
+	return ""
+}
+
+// blockContent returns the content built from the structured content block
+// set in the model. It reports false if no block is set, and returns an
+// unknown value while any of the block's values is unknown.
+func (m DNSRecordResourceModel) blockContent(ctx context.Context) (types.String, bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	switch {
+	case m.SRV != nil:
+		if !allKnown(m.SRV.Weight, m.SRV.Port, m.SRV.Target) {
+			return types.StringUnknown(), true, diags
+		}
+		return types.StringValue(m.SRV.record().content()), true, diags
+
+	case m.CAA != nil:
+		if !allKnown(m.CAA.Flags, m.CAA.Tag, m.CAA.Value) {
+			return types.StringUnknown(), true, diags
+		}
+		return types.StringValue(m.CAA.record().content()), true, diags
+
+	case m.TLSA != nil:
+		if !allKnown(m.TLSA.Usage, m.TLSA.Selector, m.TLSA.MatchingType, m.TLSA.Data) {
+			return types.StringUnknown(), true, diags
+		}
+		return types.StringValue(m.TLSA.record().content()), true, diags
+
+	case m.SVCB != nil:
+		if !allKnown(m.SVCB.Priority, m.SVCB.Target, m.SVCB.Params) {
+			return types.StringUnknown(), true, diags
+		}
+		record, d := m.SVCB.record(ctx)
+		diags.Append(d...)
+		return types.StringValue(record.content()), true, diags
+	}
+
+	return types.StringNull(), false, diags
+}
+
+// recordName returns the subdomain to send to the API, in punycode: name,
+// without the domain if it is fully qualified, or for an srv block, the
+// _service._proto name under it.
+func (m DNSRecordResourceModel) recordName() string {
+	name := porkbun.Subdomain(m.Name.ValueASCII(), m.Domain.ValueASCII())
+	if m.SRV != nil {
+		return srvRecordName(m.SRV.Service.ValueString(), m.SRV.Proto.ValueString(), name)
+	}
+	return name
+}
+
+// fqdn returns the fully-qualified name of the record in lowercase punycode,
+// or an unknown value while any part of it is unknown.
+func (m DNSRecordResourceModel) fqdn() types.String {
+	if !allKnown(m.Domain, m.Name) || (m.SRV != nil && !allKnown(m.SRV.Service, m.SRV.Proto)) {
+		return types.StringUnknown()
+	}
+	return types.StringValue(strings.ToLower(recordFQDN(m.recordName(), m.Domain.ValueASCII())))
+}
+
+// readRecord sets the name and content of the model from a record's name,
+// relative to the domain, and content as returned by the API. Structured
+// content blocks in the model are parsed back from the content; values that
+// only differ in formatting keep their prior form so they do not show up as
+// drift. A block whose content no longer parses is dropped from state.
+func (m *DNSRecordResourceModel) readRecord(ctx context.Context, recordType, name, content string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if m.SRV != nil {
+		if service, proto, rest, ok := parseSRVRecordName(name); ok {
+			if !strings.EqualFold(service, strings.TrimPrefix(m.SRV.Service.ValueString(), "_")) {
+				m.SRV.Service = types.StringValue(service)
+			}
+			if !strings.EqualFold(proto, strings.TrimPrefix(m.SRV.Proto.ValueString(), "_")) {
+				m.SRV.Proto = types.StringValue(proto)
+			}
+			name = rest
+		} else {
+			m.SRV = nil
+		}
+	}
+	// Keep the name as configured if it is the same one written differently,
+	// such as fully qualified
+	if m.Name.IsNull() || !strings.EqualFold(porkbun.Subdomain(m.Name.ValueASCII(), m.Domain.ValueASCII()), mustIDNToASCII(name)) {
+		m.Name = NewIDNStringValue(name)
+	}
+
+	switch {
+	case m.SRV != nil:
+		if record, err := parseSRVContent(content); err != nil {
+			m.SRV = nil
+		} else if record.normalize() != m.SRV.record().normalize() {
+			m.SRV.Weight = types.Int64Value(record.Weight)
+			m.SRV.Port = types.Int64Value(record.Port)
+			m.SRV.Target = types.StringValue(record.Target)
+		}
+
+	case m.CAA != nil:
+		if record, err := parseCAAContent(content); err != nil {
+			m.CAA = nil
+		} else if record.normalize() != m.CAA.record().normalize() {
+			m.CAA.Flags = types.Int64Value(record.Flags)
+			m.CAA.Tag = types.StringValue(record.Tag)
+			m.CAA.Value = types.StringValue(record.Value)
+		}
+
+	case m.TLSA != nil:
+		if record, err := parseTLSAContent(content); err != nil {
+			m.TLSA = nil
+		} else if record.normalize() != m.TLSA.record().normalize() {
+			m.TLSA.Usage = types.Int64Value(record.Usage)
+			m.TLSA.Selector = types.Int64Value(record.Selector)
+			m.TLSA.MatchingType = types.Int64Value(record.MatchingType)
+			m.TLSA.Data = types.StringValue(record.Data)
+		}
+
+	case m.SVCB != nil:
+		prior, d := m.SVCB.record(ctx)
+		diags.Append(d...)
+		if record, err := parseSVCBContent(content); err != nil {
+			m.SVCB = nil
+		} else if !record.equal(prior) {
+			m.SVCB.Priority = types.Int64Value(record.Priority)
+			m.SVCB.Target = types.StringValue(record.Target)
+			m.SVCB.Params = types.MapNull(types.StringType)
+			if len(record.Params) > 0 {
+				params, d := types.MapValueFrom(ctx, types.StringType, record.Params)
+				diags.Append(d...)
+				m.SVCB.Params = params
+			}
+		}
+	}
+
+	// Keep content in step with the block it was built from
+	if blockContent, ok, d := m.blockContent(ctx); ok {
+		diags.Append(d...)
+		m.Content = IDNStringValue{StringValue: blockContent}
+		return diags
+	}
+
+	if recordType == "TXT" {
+		m.Content = NewIDNStringValue(readTXTContent(m.Content.ValueString(), content))
+	} else {
+		m.Content = NewIDNStringValue(stateContent(recordType, content))
+	}
+
+	return diags
+}
+
+func (m SRVModel) record() srvRecord {
+	return srvRecord{
+		Weight: m.Weight.ValueInt64(),
+		Port:   m.Port.ValueInt64(),
+		Target: m.Target.ValueString(),
+	}
+}
+
+func (m CAAModel) record() caaRecord {
+	return caaRecord{
+		Flags: m.Flags.ValueInt64(),
+		Tag:   m.Tag.ValueString(),
+		Value: m.Value.ValueString(),
+	}
+}
+
+func (m TLSAModel) record() tlsaRecord {
+	return tlsaRecord{
+		Usage:        m.Usage.ValueInt64(),
+		Selector:     m.Selector.ValueInt64(),
+		MatchingType: m.MatchingType.ValueInt64(),
+		Data:         m.Data.ValueString(),
+	}
+}
+
+func (m SVCBModel) record(ctx context.Context) (svcbRecord, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	params := make(map[string]string)
+	if !m.Params.IsNull() {
+		diags.Append(m.Params.ElementsAs(ctx, &params, false)...)
+	}
+
+	return svcbRecord{
+		Priority: m.Priority.ValueInt64(),
+		Target:   m.Target.ValueString(),
+		Params:   params,
+	}, diags
+}
+
+// allKnown reports whether none of values, or the elements of maps among
+// them, is unknown.
+func allKnown(values ...attr.Value) bool {
+	for _, v := range values {
+		if v.IsUnknown() {
+			return false
+		}
+		if m, ok := v.(types.Map); ok {
+			for _, elem := range m.Elements() {
+				if elem.IsUnknown() {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
@@ -7,8 +7,10 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -20,19 +22,20 @@ func NewDNSRecordDataSource() datasource.DataSource {
 
 // DNSRecordDataSource defines the data source implementation.
 type DNSRecordDataSource struct {
-	client *Client
+	client *porkbun.Client
 }
 
 // DNSRecordDataSourceModel describes the data source data model.
 type DNSRecordDataSourceModel struct {
-	ID      types.String `tfsdk:"id"`
-	Domain  types.String `tfsdk:"domain"`
-	Name    types.String `tfsdk:"name"`
-	Type    types.String `tfsdk:"type"`
-	Content types.String `tfsdk:"content"`
-	TTL     types.String `tfsdk:"ttl"`
-	Prio    types.String `tfsdk:"prio"`
-	Notes   types.String `tfsdk:"notes"`
+	ID      types.String   `tfsdk:"id"`
+	Domain  IDNStringValue `tfsdk:"domain"`
+	Name    types.String   `tfsdk:"name"`
+	FQDN    types.String   `tfsdk:"fqdn"`
+	Type    types.String   `tfsdk:"type"`
+	Content types.String   `tfsdk:"content"`
+	TTL     types.String   `tfsdk:"ttl"`
+	Prio    types.String   `tfsdk:"prio"`
+	Notes   types.String   `tfsdk:"notes"`
 }
 
 func (d *DNSRecordDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -48,13 +51,21 @@ func (d *DNSRecordDataSource) Schema(ctx context.Context, req datasource.SchemaR
 				Required:    true,
 			},
 			"domain": schema.StringAttribute{
-				Description: "The domain name for the DNS record (e.g., example.com).",
+				Description: "The domain name for the DNS record (e.g., example.com). Internationalized names may be written in Unicode.",
 				Required:    true,
+				CustomType:  IDNStringType{},
+				Validators: []validator.String{
+					validIDN(),
+				},
 			},
 			"name": schema.StringAttribute{
 				Description: "The subdomain for the record.",
 				Computed:    true,
 			},
+			"fqdn": schema.StringAttribute{
+				Description: "The fully-qualified name of the record in lowercase punycode, such as www.example.com.",
+				Computed:    true,
+			},
 			"type": schema.StringAttribute{
 				Description: "The type of DNS record.",
 				Computed:    true,
@@ -85,17 +96,17 @@ func (d *DNSRecordDataSource) Configure(ctx context.Context, req datasource.Conf
 		return
 	}
 
-	client, ok := req.ProviderData.(*Client)
+	data, ok := req.ProviderData.(*providerData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	d.client = client
+	d.client = data.client
 }
 
 func (d *DNSRecordDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -113,24 +124,16 @@ func (d *DNSRecordDataSource) Read(ctx context.Context, req datasource.ReadReque
 		"domain": data.Domain.ValueString(),
 	})
 
-	record, err := d.client.GetDNSRecord(data.Domain.ValueString(), data.ID.ValueString())
+	record, err := d.client.GetDNSRecord(ctx, data.Domain.ValueASCII(), data.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read DNS record: %s", err))
 		return
 	}
 
-	// Extract the subdomain from the full name
-	name := record.Name
-	domain := data.Domain.ValueString()
-	if name == domain {
-		name = ""
-	} else if strings.HasSuffix(name, "."+domain) {
-		name = strings.TrimSuffix(name, "."+domain)
-	}
-
-	data.Name = types.StringValue(name)
+	data.Name = types.StringValue(porkbun.IDNToUnicode(porkbun.Subdomain(record.Name, data.Domain.ValueASCII())))
+	data.FQDN = types.StringValue(strings.ToLower(record.Name))
 	data.Type = types.StringValue(record.Type)
-	data.Content = types.StringValue(record.Content)
+	data.Content = types.StringValue(stateContent(record.Type, record.Content))
 	data.TTL = types.StringValue(record.TTL)
 	data.Prio = types.StringValue(record.Prio)
 	data.Notes = types.StringValue(record.Notes)
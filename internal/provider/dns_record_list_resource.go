@@ -0,0 +1,160 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ list.ListResource = &DNSRecordListResource{}
+var _ list.ListResourceWithConfigure = &DNSRecordListResource{}
+
+func NewDNSRecordListResource() list.ListResource {
+	return &DNSRecordListResource{}
+}
+
+// DNSRecordListResource defines the list resource implementation.
+type DNSRecordListResource struct {
+	client *porkbun.Client
+}
+
+// DNSRecordListResourceModel describes the list resource config data model.
+type DNSRecordListResourceModel struct {
+	Domain IDNStringValue `tfsdk:"domain"`
+	Name   IDNStringValue `tfsdk:"name"`
+	Type   types.String   `tfsdk:"type"`
+}
+
+func (r *DNSRecordListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dns_record"
+}
+
+func (r *DNSRecordListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the DNS records of a domain in Porkbun.",
+		Attributes: map[string]schema.Attribute{
+			"domain": schema.StringAttribute{
+				Description: "The domain name to list DNS records for (e.g., example.com). Internationalized names may be written in Unicode.",
+				Required:    true,
+				CustomType:  IDNStringType{},
+				Validators: []validator.String{
+					validIDN(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "Only list records with this subdomain, or fully-qualified name. Use an empty string for the root domain.",
+				Optional:    true,
+				CustomType:  IDNStringType{},
+				Validators: []validator.String{
+					validIDN(),
+				},
+			},
+			"type": schema.StringAttribute{
+				Description: "Only list records of this type.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(dnsRecordTypes...),
+				},
+			},
+		},
+	}
+}
+
+func (r *DNSRecordListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected List Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = data.client
+}
+
+func (r *DNSRecordListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config DNSRecordListResourceModel
+
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	domain := config.Domain.ValueASCII()
+
+	tflog.Debug(ctx, "Listing DNS records", map[string]interface{}{
+		"domain": domain,
+		"name":   config.Name.ValueString(),
+		"type":   config.Type.ValueString(),
+	})
+
+	records, err := r.client.RetrieveDNSRecords(ctx, domain)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to list DNS records: %s", err))
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		for _, record := range records {
+			name := porkbun.Subdomain(record.Name, domain)
+			content := stateContent(record.Type, record.Content)
+
+			if !config.Name.IsNull() && !strings.EqualFold(name, porkbun.Subdomain(config.Name.ValueASCII(), domain)) {
+				continue
+			}
+			if !config.Type.IsNull() && record.Type != config.Type.ValueString() {
+				continue
+			}
+
+			result := req.NewListResult(ctx)
+			result.DisplayName = fmt.Sprintf("%s %s %s", porkbun.IDNToUnicode(record.Name), record.Type, content)
+
+			identity := DNSRecordResourceIdentityModel{
+				Domain: types.StringValue(domain),
+				ID:     types.StringValue(record.ID),
+			}
+			result.Diagnostics.Append(result.Identity.Set(ctx, identity)...)
+
+			if req.IncludeResource {
+				attributes := map[string]string{
+					"id":      record.ID,
+					"domain":  config.Domain.ValueString(),
+					"name":    porkbun.IDNToUnicode(name),
+					"fqdn":    strings.ToLower(record.Name),
+					"type":    record.Type,
+					"content": content,
+					"ttl":     record.TTL,
+					"prio":    record.Prio,
+					"notes":   record.Notes,
+				}
+				for attribute, value := range attributes {
+					result.Diagnostics.Append(result.Resource.SetAttribute(ctx, path.Root(attribute), value)...)
+				}
+			}
+
+			if !push(result) {
+				return
+			}
+		}
+	}
+}
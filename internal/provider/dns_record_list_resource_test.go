@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccDNSRecordListResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		Steps: []resource.TestStep{
+			// Create a record to be discovered
+			{
+				Config: testAccDNSRecordResourceConfig_TXT("tftest-list", "v=list1"),
+			},
+			// Query records filtered by name and type
+			{
+				Query:  true,
+				Config: testAccDNSRecordListResourceConfig("tftest-list", "TXT"),
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLength("porkbun_dns_record.test", 1),
+					querycheck.ExpectIdentity("porkbun_dns_record.test", map[string]knownvalue.Check{
+						"domain": knownvalue.StringExact(testDomain),
+						"id":     knownvalue.NotNull(),
+					}),
+				},
+			},
+		},
+	})
+}
+
+func testAccDNSRecordListResourceConfig(name, recordType string) string {
+	return fmt.Sprintf(`
+provider "porkbun" {}
+
+list "porkbun_dns_record" "test" {
+  provider = porkbun
+
+  config {
+    domain = %[1]q
+    name   = %[2]q
+    type   = %[3]q
+  }
+}
+`, testDomain, name, recordType)
+}
@@ -2,12 +2,21 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
@@ -15,11 +24,29 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &DNSRecordResource{}
 var _ resource.ResourceWithImportState = &DNSRecordResource{}
+var _ resource.ResourceWithIdentity = &DNSRecordResource{}
+var _ resource.ResourceWithConfigValidators = &DNSRecordResource{}
+var _ resource.ResourceWithValidateConfig = &DNSRecordResource{}
+var _ resource.ResourceWithModifyPlan = &DNSRecordResource{}
+
+// defaultDNSRecordTimeout applies to create, update and delete when the
+// timeouts block does not set one.
+const defaultDNSRecordTimeout = 10 * time.Minute
+
+// Defaults for the wait_for_propagation block.
+const (
+	defaultPropagationInterval = 5 * time.Second
+	defaultPropagationTimeout  = 5 * time.Minute
+)
+
+// dnsRecordTypes are the record types supported by the Porkbun API.
+var dnsRecordTypes = []string{"A", "MX", "CNAME", "ALIAS", "TXT", "NS", "AAAA", "SRV", "TLSA", "CAA", "HTTPS", "SVCB"}
 
 func NewDNSRecordResource() resource.Resource {
 	return &DNSRecordResource{}
@@ -27,19 +54,41 @@ func NewDNSRecordResource() resource.Resource {
 
 // DNSRecordResource defines the resource implementation.
 type DNSRecordResource struct {
-	client *Client
+	client   *porkbun.Client
+	dns      *dnsQuerier
+	defaults DefaultsModel
 }
 
 // DNSRecordResourceModel describes the resource data model.
 type DNSRecordResourceModel struct {
-	ID      types.String `tfsdk:"id"`
-	Domain  types.String `tfsdk:"domain"`
-	Name    types.String `tfsdk:"name"`
-	Type    types.String `tfsdk:"type"`
-	Content types.String `tfsdk:"content"`
-	TTL     types.String `tfsdk:"ttl"`
-	Prio    types.String `tfsdk:"prio"`
-	Notes   types.String `tfsdk:"notes"`
+	ID                 types.String             `tfsdk:"id"`
+	Domain             IDNStringValue           `tfsdk:"domain"`
+	Name               IDNStringValue           `tfsdk:"name"`
+	FQDN               types.String             `tfsdk:"fqdn"`
+	Type               types.String             `tfsdk:"type"`
+	Content            IDNStringValue           `tfsdk:"content"`
+	TTL                types.String             `tfsdk:"ttl"`
+	Prio               types.String             `tfsdk:"prio"`
+	Notes              types.String             `tfsdk:"notes"`
+	SRV                *SRVModel                `tfsdk:"srv"`
+	CAA                *CAAModel                `tfsdk:"caa"`
+	TLSA               *TLSAModel               `tfsdk:"tlsa"`
+	SVCB               *SVCBModel               `tfsdk:"svcb"`
+	WaitForPropagation *WaitForPropagationModel `tfsdk:"wait_for_propagation"`
+	Timeouts           timeouts.Value           `tfsdk:"timeouts"`
+}
+
+// WaitForPropagationModel describes the wait_for_propagation block.
+type WaitForPropagationModel struct {
+	Interval    types.String `tfsdk:"interval"`
+	Timeout     types.String `tfsdk:"timeout"`
+	NameServers types.List   `tfsdk:"nameservers"`
+}
+
+// DNSRecordResourceIdentityModel describes the resource identity data model.
+type DNSRecordResourceIdentityModel struct {
+	Domain types.String `tfsdk:"domain"`
+	ID     types.String `tfsdk:"id"`
 }
 
 func (r *DNSRecordResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -58,46 +107,116 @@ func (r *DNSRecordResource) Schema(ctx context.Context, req resource.SchemaReque
 				},
 			},
 			"domain": schema.StringAttribute{
-				Description: "The domain name for the DNS record (e.g., example.com).",
-				Required:    true,
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+				Description: "The domain name for the DNS record (e.g., example.com). Internationalized names may be written in Unicode. " +
+					"Defaults to the provider's defaults.domain; one of them must be set.",
+				CustomType: IDNStringType{},
+				Optional:   true,
+				Computed:   true,
+				Validators: []validator.String{
+					validIDN(),
 				},
 			},
 			"name": schema.StringAttribute{
-				Description: "The subdomain for the record, not including the domain itself. Leave empty for root domain. Use * for wildcard.",
-				Optional:    true,
+				Description: "The subdomain for the record. Leave empty for root domain. Use * for wildcard. " +
+					"A fully-qualified name within the domain, with or without a trailing dot, is accepted too.",
+				CustomType: IDNStringType{},
+				Optional:   true,
+				Computed:   true,
+				Default:    stringdefault.StaticString(""),
+				Validators: []validator.String{
+					validRecordName(),
+				},
+			},
+			"fqdn": schema.StringAttribute{
+				Description: "The fully-qualified name of the record in lowercase punycode, such as www.example.com.",
 				Computed:    true,
-				Default:     stringdefault.StaticString(""),
 			},
 			"type": schema.StringAttribute{
 				Description: "The type of DNS record. Valid types are: A, MX, CNAME, ALIAS, TXT, NS, AAAA, SRV, TLSA, CAA, HTTPS, SVCB.",
 				Required:    true,
 				Validators: []validator.String{
-					stringvalidator.OneOf("A", "MX", "CNAME", "ALIAS", "TXT", "NS", "AAAA", "SRV", "TLSA", "CAA", "HTTPS", "SVCB"),
+					stringvalidator.OneOf(dnsRecordTypes...),
 				},
 			},
 			"content": schema.StringAttribute{
-				Description: "The answer content for the record.",
-				Required:    true,
+				Description: "The answer content for the record. Exactly one of content or a structured content block " +
+					"(srv, caa, tlsa or svcb) must be set; with a block, content is built from it. " +
+					"Host names in CNAME, ALIAS, NS, MX, SRV, HTTPS and SVCB content may be written in Unicode.",
+				CustomType: IDNStringType{},
+				Optional:   true,
+				Computed:   true,
 			},
 			"ttl": schema.StringAttribute{
-				Description: "The time to live in seconds for the record. Minimum and default is 600.",
-				Optional:    true,
-				Computed:    true,
-				Default:     stringdefault.StaticString("600"),
+				Description: "The time to live in seconds for the record. Minimum is 600. " +
+					"Defaults to the provider's defaults.ttl, or 600.",
+				Optional: true,
+				Computed: true,
 			},
 			"prio": schema.StringAttribute{
-				Description: "The priority of the record for those that support it (e.g., MX, SRV).",
-				Optional:    true,
-				Computed:    true,
-				Default:     stringdefault.StaticString("0"),
+				Description: "The priority of the record for those that support it (e.g., MX, SRV). " +
+					"Defaults to the provider's defaults.prio for the record type, or 0.",
+				Optional: true,
+				Computed: true,
 			},
 			"notes": schema.StringAttribute{
-				Description: "Notes for the DNS record.",
+				Description: "Notes for the DNS record. Defaults to the provider's defaults.notes, or empty.",
 				Optional:    true,
 				Computed:    true,
-				Default:     stringdefault.StaticString(""),
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"wait_for_propagation": schema.SingleNestedBlock{
+				Description: "Wait after creating or updating the record until every authoritative name server of the domain serves it, " +
+					"so that it can be used straight away.",
+				Attributes: map[string]schema.Attribute{
+					"interval": schema.StringAttribute{
+						Description: "How long to wait between checks, as a duration such as \"10s\". Defaults to \"5s\".",
+						Optional:    true,
+						Validators: []validator.String{
+							positiveDuration(),
+						},
+					},
+					"timeout": schema.StringAttribute{
+						Description: "How long to wait for the record before failing, as a duration such as \"10m\". Defaults to \"5m\".",
+						Optional:    true,
+						Validators: []validator.String{
+							positiveDuration(),
+						},
+					},
+					"nameservers": schema.ListAttribute{
+						Description: "The name servers to check, as host names, IP addresses or host:port. " +
+							"Defaults to the domain's name servers as configured at Porkbun.",
+						Optional:    true,
+						ElementType: types.StringType,
+						Validators: []validator.List{
+							listvalidator.SizeAtLeast(1),
+						},
+					},
+				},
+			},
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+
+	for name, block := range dnsRecordContentBlocks() {
+		resp.Schema.Blocks[name] = block
+	}
+}
+
+func (r *DNSRecordResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"domain": identityschema.StringAttribute{
+				Description:       "The domain name for the DNS record (e.g., example.com).",
+				RequiredForImport: true,
+			},
+			"id": identityschema.StringAttribute{
+				Description:       "The ID of the DNS record.",
+				RequiredForImport: true,
 			},
 		},
 	}
@@ -109,17 +228,147 @@ func (r *DNSRecordResource) Configure(ctx context.Context, req resource.Configur
 		return
 	}
 
-	client, ok := req.ProviderData.(*Client)
+	data, ok := req.ProviderData.(*providerData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	r.client = client
+	r.client = data.client
+	r.dns = data.dns
+	r.defaults = data.defaults
+}
+
+func (r *DNSRecordResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("content"),
+			path.MatchRoot("srv"),
+			path.MatchRoot("caa"),
+			path.MatchRoot("tlsa"),
+			path.MatchRoot("svcb"),
+		),
+	}
+}
+
+func (r *DNSRecordResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data DNSRecordResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only the host names in content are internationalized names; other
+	// content, such as TXT values, may hold any Unicode text
+	if !data.Content.IsNull() && !data.Content.IsUnknown() && !data.Type.IsUnknown() {
+		if host, ok := contentHostName(data.Type.ValueString(), data.Content.ValueString()); ok {
+			if _, err := idnToASCII(host); err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("content"), "Invalid Internationalized Domain Name", err.Error())
+			}
+		}
+	}
+
+	block := data.contentBlock()
+	if block == "" || data.Type.IsUnknown() {
+		return
+	}
+
+	for _, recordType := range contentBlockTypes[block] {
+		if data.Type.ValueString() == recordType {
+			return
+		}
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		path.Root(block),
+		"Invalid Content Block",
+		fmt.Sprintf("The %s block can only be used with records of type %s, not %s.",
+			block, strings.Join(contentBlockTypes[block], " or "), data.Type.ValueString()),
+	)
+}
+
+func (r *DNSRecordResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var data, config DNSRecordResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Attributes left out of the configuration take the provider's defaults,
+	// so that the plan shows the values that will be used
+	if config.Domain.IsNull() {
+		if r.defaults.Domain.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("domain"),
+				"Missing Domain",
+				"The record does not set domain, and the provider has no default. "+
+					"Set domain on the resource or in the provider's defaults block.",
+			)
+			return
+		}
+		data.Domain = r.defaults.Domain
+	}
+	if config.TTL.IsNull() {
+		data.TTL = r.defaults.ttl()
+	}
+	if config.Prio.IsNull() {
+		data.Prio = r.defaults.prio(data.Type)
+	}
+	if config.Notes.IsNull() {
+		data.Notes = r.defaults.notes()
+	}
+
+	// A name with a trailing dot must be within the domain
+	if !data.Name.IsUnknown() && !data.Domain.IsUnknown() {
+		name := data.Name.ValueASCII()
+		if strings.HasSuffix(name, ".") && porkbun.Subdomain(name, data.Domain.ValueASCII()) == strings.TrimSuffix(name, ".") {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("name"),
+				"Invalid Record Name",
+				fmt.Sprintf("The name %q is fully qualified but not within the domain %q. "+
+					"Leave out the trailing dot to use it as a subdomain.", data.Name.ValueString(), data.Domain.ValueString()),
+			)
+			return
+		}
+	}
+	data.FQDN = data.fqdn()
+
+	// Moving a record to another domain means creating it again, but writing
+	// the same domain in Unicode rather than punycode does not
+	if !req.State.Raw.IsNull() {
+		var prior IDNStringValue
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("domain"), &prior)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if data.Domain.IsUnknown() || !idnEqual(prior.ValueString(), data.Domain.ValueString()) {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("domain"))
+		}
+	}
+
+	// Content set through a structured block is built from it
+	content, ok, diags := data.blockContent(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if ok {
+		data.Content = IDNStringValue{StringValue: content}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &data)...)
 }
 
 func (r *DNSRecordResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -132,25 +381,34 @@ func (r *DNSRecordResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
-	createReq := CreateDNSRecordRequest{
-		Name:    data.Name.ValueString(),
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultDNSRecordTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	createReq := porkbun.CreateDNSRecordRequest{
+		Name:    data.recordName(),
 		Type:    data.Type.ValueString(),
-		Content: data.Content.ValueString(),
+		Content: apiContent(data.Type.ValueString(), data.Content.ValueString()),
 		TTL:     data.TTL.ValueString(),
 		Prio:    data.Prio.ValueString(),
 		Notes:   data.Notes.ValueString(),
 	}
 
 	tflog.Debug(ctx, "Creating DNS record", map[string]interface{}{
-		"domain":  data.Domain.ValueString(),
+		"domain":  data.Domain.ValueASCII(),
 		"name":    createReq.Name,
 		"type":    createReq.Type,
 		"content": createReq.Content,
 	})
 
-	id, err := r.client.CreateDNSRecord(data.Domain.ValueString(), createReq)
+	id, err := r.client.CreateDNSRecord(ctx, data.Domain.ValueASCII(), createReq)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create DNS record: %s", err))
+		addClientError(&resp.Diagnostics, "Unable to create DNS record", err)
 		return
 	}
 
@@ -162,6 +420,17 @@ func (r *DNSRecordResource) Create(ctx context.Context, req resource.CreateReque
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+
+	identity := DNSRecordResourceIdentityModel{
+		Domain: types.StringValue(data.Domain.ValueASCII()),
+		ID:     data.ID,
+	}
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, identity)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.waitForPropagation(ctx, data)...)
 }
 
 func (r *DNSRecordResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -174,10 +443,10 @@ func (r *DNSRecordResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	record, err := r.client.GetDNSRecord(data.Domain.ValueString(), data.ID.ValueString())
+	record, err := r.client.GetDNSRecord(ctx, data.Domain.ValueASCII(), data.ID.ValueString())
 	if err != nil {
 		// Check if the record was deleted outside of Terraform
-		if strings.Contains(err.Error(), "not found") {
+		if errors.Is(err, porkbun.ErrNotFound) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -185,24 +454,22 @@ func (r *DNSRecordResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	// Extract the subdomain from the full name
-	name := record.Name
-	domain := data.Domain.ValueString()
-	if name == domain {
-		name = ""
-	} else if strings.HasSuffix(name, "."+domain) {
-		name = strings.TrimSuffix(name, "."+domain)
-	}
-
-	data.Name = types.StringValue(name)
 	data.Type = types.StringValue(record.Type)
-	data.Content = types.StringValue(record.Content)
+	name := porkbun.IDNToUnicode(porkbun.Subdomain(record.Name, data.Domain.ValueASCII()))
+	resp.Diagnostics.Append(data.readRecord(ctx, record.Type, name, record.Content)...)
+	data.FQDN = data.fqdn()
 	data.TTL = types.StringValue(record.TTL)
 	data.Prio = types.StringValue(record.Prio)
 	data.Notes = types.StringValue(record.Notes)
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+
+	identity := DNSRecordResourceIdentityModel{
+		Domain: types.StringValue(data.Domain.ValueASCII()),
+		ID:     data.ID,
+	}
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, identity)...)
 }
 
 func (r *DNSRecordResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -215,10 +482,19 @@ func (r *DNSRecordResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
-	editReq := EditDNSRecordRequest{
-		Name:    data.Name.ValueString(),
+	updateTimeout, diags := data.Timeouts.Update(ctx, defaultDNSRecordTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	editReq := porkbun.EditDNSRecordRequest{
+		Name:    data.recordName(),
 		Type:    data.Type.ValueString(),
-		Content: data.Content.ValueString(),
+		Content: apiContent(data.Type.ValueString(), data.Content.ValueString()),
 		TTL:     data.TTL.ValueString(),
 		Prio:    data.Prio.ValueString(),
 		Notes:   data.Notes.ValueString(),
@@ -226,20 +502,25 @@ func (r *DNSRecordResource) Update(ctx context.Context, req resource.UpdateReque
 
 	tflog.Debug(ctx, "Updating DNS record", map[string]interface{}{
 		"id":      data.ID.ValueString(),
-		"domain":  data.Domain.ValueString(),
+		"domain":  data.Domain.ValueASCII(),
 		"name":    editReq.Name,
 		"type":    editReq.Type,
 		"content": editReq.Content,
 	})
 
-	err := r.client.EditDNSRecord(data.Domain.ValueString(), data.ID.ValueString(), editReq)
+	err := r.client.EditDNSRecord(ctx, data.Domain.ValueASCII(), data.ID.ValueString(), editReq)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update DNS record: %s", err))
+		addClientError(&resp.Diagnostics, "Unable to update DNS record", err)
 		return
 	}
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.waitForPropagation(ctx, data)...)
 }
 
 func (r *DNSRecordResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -252,29 +533,298 @@ func (r *DNSRecordResource) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, defaultDNSRecordTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	tflog.Debug(ctx, "Deleting DNS record", map[string]interface{}{
 		"id":     data.ID.ValueString(),
-		"domain": data.Domain.ValueString(),
+		"domain": data.Domain.ValueASCII(),
 	})
 
-	err := r.client.DeleteDNSRecord(data.Domain.ValueString(), data.ID.ValueString())
+	err := r.client.DeleteDNSRecord(ctx, data.Domain.ValueASCII(), data.ID.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete DNS record: %s", err))
+		addClientError(&resp.Diagnostics, "Unable to delete DNS record", err)
 		return
 	}
 }
 
-func (r *DNSRecordResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Import format: domain/record_id
-	parts := strings.Split(req.ID, "/")
-	if len(parts) != 2 {
-		resp.Diagnostics.AddError(
-			"Invalid Import ID",
-			fmt.Sprintf("Expected import ID in format 'domain/record_id', got: %s", req.ID),
+// waitForPropagation waits, if the wait_for_propagation block is set, until
+// every authoritative name server of the domain serves the record.
+func (r *DNSRecordResource) waitForPropagation(ctx context.Context, data DNSRecordResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if data.WaitForPropagation == nil {
+		return diags
+	}
+
+	interval := defaultPropagationInterval
+	if v := data.WaitForPropagation.Interval; !v.IsNull() {
+		interval, _ = time.ParseDuration(v.ValueString())
+	}
+	timeout := defaultPropagationTimeout
+	if v := data.WaitForPropagation.Timeout; !v.IsNull() {
+		timeout, _ = time.ParseDuration(v.ValueString())
+	}
+
+	domain := data.Domain.ValueASCII()
+
+	var nameservers []string
+	if !data.WaitForPropagation.NameServers.IsNull() {
+		diags.Append(data.WaitForPropagation.NameServers.ElementsAs(ctx, &nameservers, false)...)
+		if diags.HasError() {
+			return diags
+		}
+	} else {
+		var err error
+		nameservers, err = r.client.GetNameServers(ctx, domain)
+		if err != nil {
+			diags.AddWarning(
+				"DNS Record Propagation Not Checked",
+				fmt.Sprintf("The DNS record was saved, but the name servers to check could not be read: %s", err),
+			)
+			return diags
+		}
+	}
+
+	fqdn := recordFQDN(data.recordName(), domain)
+
+	tflog.Debug(ctx, "Waiting for DNS record propagation", map[string]interface{}{
+		"name":        fqdn,
+		"type":        data.Type.ValueString(),
+		"nameservers": nameservers,
+		"interval":    interval.String(),
+		"timeout":     timeout.String(),
+	})
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	content := apiContent(data.Type.ValueString(), data.Content.ValueString())
+	// The record has been saved by now, so failing would taint it, and the
+	// next apply would delete and recreate it
+	err := r.dns.waitForRecord(ctx, nameservers, fqdn, data.Type.ValueString(), content, data.Prio.ValueString(), interval)
+	if err != nil {
+		diags.AddWarning(
+			"DNS Record Not Propagated",
+			fmt.Sprintf("The DNS record was saved, but not every authoritative name server served it in time: %s", err),
 		)
+	}
+
+	return diags
+}
+
+func (r *DNSRecordResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import by identity (import block with an identity attribute)
+	if req.ID == "" {
+		var identity DNSRecordResourceIdentityModel
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("domain"), identity.Domain)...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), identity.ID)...)
+		return
+	}
+
+	importID, err := parseDNSRecordImportID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("Unable to parse import ID: %s", err))
 		return
 	}
 
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("domain"), parts[0])...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), parts[1])...)
+	recordID := importID.RecordID
+	if recordID == "" {
+		records, err := r.client.RetrieveDNSRecords(ctx, mustIDNToASCII(importID.Domain))
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list DNS records: %s", err))
+			return
+		}
+
+		recordID, err = importID.selectRecord(records)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Resolve Import ID", fmt.Sprintf("Unable to import DNS record: %s", err))
+			return
+		}
+
+		tflog.Debug(ctx, "Resolved DNS record import ID", map[string]interface{}{
+			"import_id": req.ID,
+			"id":        recordID,
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("domain"), importID.Domain)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), recordID)...)
+
+	identity := DNSRecordResourceIdentityModel{
+		Domain: types.StringValue(mustIDNToASCII(importID.Domain)),
+		ID:     types.StringValue(recordID),
+	}
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, identity)...)
+}
+
+// apiContent returns the content to send to the API for a record, splitting
+// long TXT values into quoted strings and converting internationalized host
+// names to punycode.
+func apiContent(recordType, content string) string {
+	switch recordType {
+	case "TXT":
+		return porkbun.EncodeTXTContent(content)
+	case "CNAME", "ALIAS", "NS", "MX":
+		return mustIDNToASCII(content)
+	case "SRV":
+		// The target is the last field of "weight port target"
+		if fields := strings.Fields(content); len(fields) == 3 {
+			fields[2] = mustIDNToASCII(fields[2])
+			return strings.Join(fields, " ")
+		}
+	case "HTTPS", "SVCB":
+		// The target is the second field of "priority target params..."
+		if fields := strings.Fields(content); len(fields) >= 2 {
+			fields[1] = mustIDNToASCII(fields[1])
+			return strings.Join(fields, " ")
+		}
+	}
+	return content
+}
+
+// contentHostName returns the host name in the content of a record of
+// recordType, and false if the type has none.
+func contentHostName(recordType, content string) (string, bool) {
+	switch recordType {
+	case "CNAME", "ALIAS", "NS", "MX":
+		return content, true
+	case "SRV":
+		if fields := strings.Fields(content); len(fields) == 3 {
+			return fields[2], true
+		}
+	case "HTTPS", "SVCB":
+		if fields := strings.Fields(content); len(fields) >= 2 {
+			return fields[1], true
+		}
+	}
+	return "", false
+}
+
+// stateContent returns the content to show for a record read from the
+// API: TXT values joined back together, and host names in Unicode.
+func stateContent(recordType, content string) string {
+	switch recordType {
+	case "TXT":
+		return porkbun.DecodeTXTContent(content)
+	case "CNAME", "ALIAS", "NS", "MX":
+		return porkbun.IDNToUnicode(content)
+	case "SRV":
+		if fields := strings.Fields(content); len(fields) == 3 {
+			fields[2] = porkbun.IDNToUnicode(fields[2])
+			return strings.Join(fields, " ")
+		}
+	case "HTTPS", "SVCB":
+		if fields := strings.Fields(content); len(fields) >= 2 {
+			fields[1] = porkbun.IDNToUnicode(fields[1])
+			return strings.Join(fields, " ")
+		}
+	}
+	return content
+}
+
+// recordFQDN returns the fully-qualified name of the record named name,
+// relative to domain.
+func recordFQDN(name, domain string) string {
+	domain = strings.TrimSuffix(domain, ".")
+	if name == "" {
+		return domain
+	}
+	return name + "." + domain
+}
+
+// dnsRecordImportID is a parsed porkbun_dns_record import ID. It either names
+// a record directly by ID or selects one by name, type and optionally content.
+type dnsRecordImportID struct {
+	Domain     string
+	RecordID   string
+	Name       string
+	Type       string
+	Content    string
+	HasContent bool
+}
+
+// parseDNSRecordImportID parses an import ID in one of the formats
+// domain/record_id, domain/name/type or domain/name/type/content. The root
+// of the domain can be written as an empty name or @. Content may itself
+// contain slashes.
+func parseDNSRecordImportID(id string) (dnsRecordImportID, error) {
+	const formats = "'domain/record_id', 'domain/name/type' or 'domain/name/type/content'"
+
+	parts := strings.Split(id, "/")
+	if len(parts) < 2 || parts[0] == "" {
+		return dnsRecordImportID{}, fmt.Errorf("expected import ID in format %s, got: %s", formats, id)
+	}
+
+	importID := dnsRecordImportID{Domain: parts[0]}
+
+	if len(parts) == 2 {
+		if _, err := strconv.ParseInt(parts[1], 10, 64); err != nil {
+			return dnsRecordImportID{}, fmt.Errorf("expected a numeric record ID in import ID 'domain/record_id', got: %s", id)
+		}
+		importID.RecordID = parts[1]
+		return importID, nil
+	}
+
+	if parts[2] == "" {
+		return dnsRecordImportID{}, fmt.Errorf("expected a record type in import ID %s, got: %s", formats, id)
+	}
+
+	importID.Name = parts[1]
+	if importID.Name == "@" {
+		importID.Name = ""
+	}
+	importID.Type = strings.ToUpper(parts[2])
+
+	if len(parts) > 3 {
+		importID.Content = strings.Join(parts[3:], "/")
+		importID.HasContent = true
+	}
+
+	return importID, nil
+}
+
+// selectRecord returns the ID of the single record in records matched by
+// the import ID's name, type and content.
+func (id dnsRecordImportID) selectRecord(records []porkbun.DNSRecord) (string, error) {
+	domain := mustIDNToASCII(id.Domain)
+	fqdn := recordFQDN(porkbun.Subdomain(mustIDNToASCII(id.Name), domain), domain)
+
+	var matches []string
+	for _, record := range records {
+		if !strings.EqualFold(record.Name, fqdn) || !strings.EqualFold(record.Type, id.Type) {
+			continue
+		}
+		if id.HasContent && !porkbun.SameContent(record.Type, record.Content, apiContent(record.Type, id.Content)) {
+			continue
+		}
+		matches = append(matches, record.ID)
+	}
+
+	selector := fmt.Sprintf("name %q and type %s", id.Name, id.Type)
+	if id.HasContent {
+		selector = fmt.Sprintf("name %q, type %s and content %q", id.Name, id.Type, id.Content)
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no DNS record in %s matches %s", id.Domain, selector)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%d DNS records in %s match %s (IDs: %s); "+
+			"add the record content to the import ID or import by record ID instead",
+			len(matches), id.Domain, selector, strings.Join(matches, ", "))
+	}
 }
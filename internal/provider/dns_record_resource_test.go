@@ -1,11 +1,29 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/miekg/dns"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
 )
 
 // importStateIdFunc returns an ImportStateIdFunc for a given resource name
@@ -19,6 +37,84 @@ func importStateIdFunc(resourceName string) resource.ImportStateIdFunc {
 	}
 }
 
+func TestParseDNSRecordImportID(t *testing.T) {
+	cases := []struct {
+		id      string
+		want    dnsRecordImportID
+		wantErr bool
+	}{
+		{id: "example.com/123456789", want: dnsRecordImportID{Domain: "example.com", RecordID: "123456789"}},
+		{id: "example.com/www/a", want: dnsRecordImportID{Domain: "example.com", Name: "www", Type: "A"}},
+		{id: "example.com/@/MX", want: dnsRecordImportID{Domain: "example.com", Type: "MX"}},
+		{id: "example.com//TXT/v=spf1 -all", want: dnsRecordImportID{Domain: "example.com", Type: "TXT", Content: "v=spf1 -all", HasContent: true}},
+		{id: "example.com/_dmarc/TXT/v=DMARC1; rua=mailto:a@example.com/x", want: dnsRecordImportID{Domain: "example.com", Name: "_dmarc", Type: "TXT", Content: "v=DMARC1; rua=mailto:a@example.com/x", HasContent: true}},
+		{id: "example.com", wantErr: true},
+		{id: "example.com/www", wantErr: true},
+		{id: "/123", wantErr: true},
+		{id: "example.com/www/", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		got, err := parseDNSRecordImportID(tc.id)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected an error, got %+v", tc.id, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", tc.id, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%q: expected %+v, got %+v", tc.id, tc.want, got)
+		}
+	}
+}
+
+func TestDNSRecordImportID_SelectRecord(t *testing.T) {
+	records := []porkbun.DNSRecord{
+		{ID: "1", Name: "example.com", Type: "A", Content: "192.0.2.1"},
+		{ID: "2", Name: "www.example.com", Type: "A", Content: "192.0.2.1"},
+		{ID: "3", Name: "www.example.com", Type: "A", Content: "192.0.2.2"},
+		{ID: "4", Name: "www.example.com", Type: "AAAA", Content: "2001:db8::1"},
+	}
+
+	cases := []struct {
+		id      string
+		want    string
+		wantErr string
+	}{
+		{id: "example.com/@/A", want: "1"},
+		{id: "example.com/www/AAAA", want: "4"},
+		{id: "example.com/www/A/192.0.2.2", want: "3"},
+		{id: "example.com/www/A", wantErr: "2 DNS records"},
+		{id: "example.com/mail/A", wantErr: "no DNS record"},
+	}
+
+	for _, tc := range cases {
+		importID, err := parseDNSRecordImportID(tc.id)
+		if err != nil {
+			t.Fatalf("%q: unexpected parse error: %s", tc.id, err)
+		}
+
+		got, err := importID.selectRecord(records)
+		if tc.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("%q: expected error containing %q, got %v", tc.id, tc.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", tc.id, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%q: expected record %s, got %s", tc.id, tc.want, got)
+		}
+	}
+}
+
 func TestAccDNSRecordResource_A(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
@@ -43,6 +139,13 @@ func TestAccDNSRecordResource_A(t *testing.T) {
 				ImportStateVerify: true,
 				ImportStateIdFunc: importStateIdFunc("porkbun_dns_record.test"),
 			},
+			// ImportState testing by name and type
+			{
+				ResourceName:      "porkbun_dns_record.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     fmt.Sprintf("%s/tftest/A", testDomain),
+			},
 			// Update testing - change IP
 			{
 				Config: testAccDNSRecordResourceConfig_A("tftest", "192.0.2.2"),
@@ -120,6 +223,13 @@ func TestAccDNSRecordResource_TXT(t *testing.T) {
 					resource.TestCheckResourceAttrSet("porkbun_dns_record.test_txt", "id"),
 				),
 			},
+			// ImportState testing by name, type and content
+			{
+				ResourceName:      "porkbun_dns_record.test_txt",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     fmt.Sprintf("%s/tftest-txt/TXT/v=test1", testDomain),
+			},
 			// Update testing - change value
 			{
 				Config: testAccDNSRecordResourceConfig_TXT("tftest-txt", "v=test2"),
@@ -132,6 +242,58 @@ func TestAccDNSRecordResource_TXT(t *testing.T) {
 	})
 }
 
+func TestAccDNSRecordResource_LongTXT(t *testing.T) {
+	dkim := "v=DKIM1; k=rsa; p=" + strings.Repeat("MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8A", 12)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing; a second plan must be empty
+			{
+				Config: testAccDNSRecordResourceConfig_TXT("tftest-dkim._domainkey", dkim),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_dns_record.test_txt", "content", dkim),
+				),
+			},
+			// ImportState testing reassembles the value
+			{
+				ResourceName:      "porkbun_dns_record.test_txt",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: importStateIdFunc("porkbun_dns_record.test_txt"),
+			},
+		},
+	})
+}
+
+func TestAccDNSRecordResource_SRVBlock(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccDNSRecordResourceConfig_SRVBlock("tftest-srv", 5060),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_dns_record.test_srv", "name", "tftest-srv"),
+					resource.TestCheckResourceAttr("porkbun_dns_record.test_srv", "content", "5 5060 sip.example.com"),
+					resource.TestCheckResourceAttr("porkbun_dns_record.test_srv", "prio", "10"),
+					resource.TestCheckResourceAttr("porkbun_dns_record.test_caa", "content", `0 issue "letsencrypt.org"`),
+				),
+			},
+			// Update testing - change port
+			{
+				Config: testAccDNSRecordResourceConfig_SRVBlock("tftest-srv", 5061),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_dns_record.test_srv", "srv.port", "5061"),
+					resource.TestCheckResourceAttr("porkbun_dns_record.test_srv", "content", "5 5061 sip.example.com"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccDNSRecordResource_MX(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
@@ -233,6 +395,81 @@ func TestAccDNSRecordResource_CustomTTL(t *testing.T) {
 	})
 }
 
+func TestAccDNSRecordResource_ProviderDefaults(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create with domain, TTL, notes and priority from the provider
+			{
+				Config: testAccDNSRecordResourceConfig_ProviderDefaults("3600"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_dns_record.test_defaults", "domain", testDomain),
+					resource.TestCheckResourceAttr("porkbun_dns_record.test_defaults", "ttl", "3600"),
+					resource.TestCheckResourceAttr("porkbun_dns_record.test_defaults", "prio", "20"),
+					resource.TestCheckResourceAttr("porkbun_dns_record.test_defaults", "notes", "Managed by Terraform"),
+					resource.TestCheckResourceAttrSet("porkbun_dns_record.test_defaults", "id"),
+				),
+			},
+			// Changing the provider default updates the record
+			{
+				Config: testAccDNSRecordResourceConfig_ProviderDefaults("7200"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_dns_record.test_defaults", "ttl", "7200"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccDNSRecordResource_FQDN(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create with a fully-qualified name
+			{
+				Config: testAccDNSRecordResourceConfig_A("tftest-fqdn."+testDomain+".", "192.0.2.30"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_dns_record.test", "name", "tftest-fqdn."+testDomain+"."),
+					resource.TestCheckResourceAttr("porkbun_dns_record.test", "fqdn", "tftest-fqdn."+testDomain),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccDNSRecordResource_Identity(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_12_0),
+		},
+		Steps: []resource.TestStep{
+			// Create and check identity
+			{
+				Config: testAccDNSRecordResourceConfig_A("tftest-identity", "192.0.2.10"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectIdentity("porkbun_dns_record.test", map[string]knownvalue.Check{
+						"domain": knownvalue.StringExact(testDomain),
+						"id":     knownvalue.NotNull(),
+					}),
+					statecheck.ExpectIdentityValueMatchesState("porkbun_dns_record.test", tfjsonpath.New("id")),
+				},
+			},
+			// Import with an import block keyed by identity
+			{
+				ResourceName:    "porkbun_dns_record.test",
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithResourceIdentity,
+			},
+		},
+	})
+}
+
 // Config helper functions
 
 func testAccDNSRecordResourceConfig_A(name, ip string) string {
@@ -283,6 +520,36 @@ resource "porkbun_dns_record" "test_txt" {
 `, testDomain, name, value)
 }
 
+func testAccDNSRecordResourceConfig_SRVBlock(name string, port int) string {
+	return fmt.Sprintf(`
+resource "porkbun_dns_record" "test_srv" {
+  domain = %[1]q
+  name   = %[2]q
+  type   = "SRV"
+  prio   = "10"
+
+  srv {
+    service = "sip"
+    proto   = "tcp"
+    weight  = 5
+    port    = %[3]d
+    target  = "sip.example.com"
+  }
+}
+
+resource "porkbun_dns_record" "test_caa" {
+  domain = %[1]q
+  name   = %[2]q
+  type   = "CAA"
+
+  caa {
+    tag   = "issue"
+    value = "letsencrypt.org"
+  }
+}
+`, testDomain, name, port)
+}
+
 func testAccDNSRecordResourceConfig_MX(name, target, priority string) string {
 	return fmt.Sprintf(`
 resource "porkbun_dns_record" "test_mx" {
@@ -332,3 +599,179 @@ resource "porkbun_dns_record" "test_ttl" {
 }
 `, testDomain, name, ip, ttl)
 }
+
+func testAccDNSRecordResourceConfig_ProviderDefaults(ttl string) string {
+	return fmt.Sprintf(`
+provider "porkbun" {
+  defaults {
+    domain = %[1]q
+    ttl    = %[2]q
+    notes  = "Managed by Terraform"
+    prio   = { MX = "20" }
+  }
+}
+
+resource "porkbun_dns_record" "test_defaults" {
+  name    = "tftest-defaults"
+  type    = "MX"
+  content = "mail.example.com"
+}
+`, testDomain, ttl)
+}
+
+func TestDNSRecordResource_WaitForPropagationTimeoutWarns(t *testing.T) {
+	// A name server that never serves the record
+	port := startTestDNSServer(t, func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		resp.Authoritative = true
+		_ = w.WriteMsg(resp)
+	})
+
+	r := &DNSRecordResource{dns: newDNSQuerier("", "")}
+	data := DNSRecordResourceModel{
+		Domain:  NewIDNStringValue("example.com"),
+		Name:    NewIDNStringValue("www"),
+		Type:    types.StringValue("A"),
+		Content: NewIDNStringValue("192.0.2.1"),
+		WaitForPropagation: &WaitForPropagationModel{
+			Interval:    types.StringValue("10ms"),
+			Timeout:     types.StringValue("100ms"),
+			NameServers: types.ListValueMust(types.StringType, []attr.Value{types.StringValue(net.JoinHostPort("127.0.0.1", port))}),
+		},
+	}
+
+	diags := r.waitForPropagation(context.Background(), data)
+	if diags.HasError() {
+		t.Fatalf("expected a propagation timeout not to fail the apply, got: %v", diags)
+	}
+	if diags.WarningsCount() != 1 || !strings.Contains(diags[0].Detail(), "not every authoritative name server served it in time") {
+		t.Fatalf("expected a warning about the propagation, got: %v", diags)
+	}
+}
+
+func TestDNSRecordResource_ValidateContentIDN(t *testing.T) {
+	ctx := context.Background()
+
+	r := &DNSRecordResource{}
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	// Validate through the provider server, so that attribute validation
+	// runs as well as ValidateConfig
+	server, err := testAccProtoV6ProviderFactories["porkbun"]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		recordType string
+		content    string
+		wantError  bool
+	}{
+		{recordType: "TXT", content: "Grüße aus München"},
+		{recordType: "CNAME", content: "shop.müller.de"},
+		{recordType: "SRV", content: "5 5060 sip.müller.de"},
+		{recordType: "CNAME", content: "müller!.de", wantError: true},
+		{recordType: "HTTPS", content: "1 cdn.müller!.de alpn=h2", wantError: true},
+	}
+
+	for _, tc := range cases {
+		values := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+		for name, typ := range objectType.AttributeTypes {
+			values[name] = tftypes.NewValue(typ, nil)
+		}
+		values["type"] = tftypes.NewValue(tftypes.String, tc.recordType)
+		values["content"] = tftypes.NewValue(tftypes.String, tc.content)
+
+		config, err := tfprotov6.NewDynamicValue(objectType, tftypes.NewValue(objectType, values))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := server.ValidateResourceConfig(ctx, &tfprotov6.ValidateResourceConfigRequest{
+			TypeName: "porkbun_dns_record",
+			Config:   &config,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Only the IDN check is of interest; the empty blocks have errors
+		// of their own
+		hasError := false
+		for _, d := range resp.Diagnostics {
+			hasError = hasError || d.Summary == "Invalid Internationalized Domain Name"
+		}
+		if hasError != tc.wantError {
+			t.Errorf("%s %q: expected an IDN error %t, got %t", tc.recordType, tc.content, tc.wantError, hasError)
+		}
+	}
+}
+
+// readTestResource calls Read on r with state holding model, and returns the
+// response.
+func readTestResource(t *testing.T, r fwresource.ResourceWithIdentity, model interface{}) *fwresource.ReadResponse {
+	t.Helper()
+	ctx := context.Background()
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	var identityResp fwresource.IdentitySchemaResponse
+	r.IdentitySchema(ctx, fwresource.IdentitySchemaRequest{}, &identityResp)
+
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+	if diags := state.Set(ctx, model); diags.HasError() {
+		t.Fatalf("unable to set state: %v", diags)
+	}
+	identity := &tfsdk.ResourceIdentity{
+		Schema: identityResp.IdentitySchema,
+		Raw:    tftypes.NewValue(identityResp.IdentitySchema.Type().TerraformType(ctx), nil),
+	}
+
+	resp := &fwresource.ReadResponse{State: state, Identity: identity}
+	r.Read(ctx, fwresource.ReadRequest{State: state, Identity: identity}, resp)
+	return resp
+}
+
+func TestDNSRecordResource_ReadNotFound(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/dns/retrieve/example.com/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"SUCCESS","records":[]}`)
+	})
+	mux.HandleFunc("/dns/retrieve/example.net/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"ERROR","message":"Domain not found in this account."}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	r := &DNSRecordResource{client: porkbun.NewClient("pk1_test", "sk1_test", porkbun.WithBaseURL(server.URL))}
+	model := func(domain string) DNSRecordResourceModel {
+		return DNSRecordResourceModel{
+			ID:       types.StringValue("1"),
+			Domain:   NewIDNStringValue(domain),
+			Name:     NewIDNStringValue("www"),
+			Type:     types.StringValue("A"),
+			Content:  NewIDNStringValue("192.0.2.1"),
+			Timeouts: timeouts.Value{Object: types.ObjectNull(map[string]attr.Type{"create": types.StringType, "update": types.StringType, "delete": types.StringType})},
+		}
+	}
+
+	// A record that no longer exists is removed from state
+	resp := readTestResource(t, r, model("example.com"))
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+	if !resp.State.Raw.IsNull() {
+		t.Fatal("expected the deleted record to be removed from state")
+	}
+
+	// Other errors are reported, even if they mention something not found
+	resp = readTestResource(t, r, model("example.net"))
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error for a failed lookup")
+	}
+	if resp.State.Raw.IsNull() {
+		t.Fatal("expected the record to stay in state after a failed lookup")
+	}
+}
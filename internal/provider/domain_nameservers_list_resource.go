@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ list.ListResource = &DomainNameServersListResource{}
+var _ list.ListResourceWithConfigure = &DomainNameServersListResource{}
+
+func NewDomainNameServersListResource() list.ListResource {
+	return &DomainNameServersListResource{}
+}
+
+// DomainNameServersListResource defines the list resource implementation.
+type DomainNameServersListResource struct {
+	client *porkbun.Client
+}
+
+func (r *DomainNameServersListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_domain_nameservers"
+}
+
+func (r *DomainNameServersListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the domains in the Porkbun account and their name servers.",
+	}
+}
+
+func (r *DomainNameServersListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected List Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = data.client
+}
+
+func (r *DomainNameServersListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	tflog.Debug(ctx, "Listing domains")
+
+	domains, err := r.client.ListAllDomains(ctx)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Client Error", fmt.Sprintf("Unable to list domains: %s", err))
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		for _, domain := range domains {
+			result := req.NewListResult(ctx)
+			result.DisplayName = porkbun.IDNToUnicode(domain.Domain)
+
+			identity := DomainNameServersResourceIdentityModel{
+				Domain: types.StringValue(domain.Domain),
+			}
+			result.Diagnostics.Append(result.Identity.Set(ctx, identity)...)
+
+			// Fetching the name servers costs one API call per domain, so
+			// only do it when Terraform asks for the full resource.
+			if req.IncludeResource {
+				nameservers, err := r.client.GetNameServers(ctx, domain.Domain)
+				if err != nil {
+					result.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read name servers for %s: %s", domain.Domain, err))
+				} else {
+					// Set as importing the identity would, so that the
+					// result matches the state the import produces
+					nsSet, diags := nameServersValue(ctx, types.SetNull(types.StringType), nameservers)
+					result.Diagnostics.Append(diags...)
+
+					result.Diagnostics.Append(result.Resource.SetAttribute(ctx, path.Root("id"), domain.Domain)...)
+					result.Diagnostics.Append(result.Resource.SetAttribute(ctx, path.Root("domain"), domain.Domain)...)
+					result.Diagnostics.Append(result.Resource.SetAttribute(ctx, path.Root("nameservers"), nsSet)...)
+					result.Diagnostics.Append(result.Resource.SetAttribute(ctx, path.Root("on_destroy"), onDestroyResetToPorkbun)...)
+					result.Diagnostics.Append(result.Resource.SetAttribute(ctx, path.Root("skip_preflight"), false)...)
+				}
+			}
+
+			if !push(result) {
+				return
+			}
+		}
+	}
+}
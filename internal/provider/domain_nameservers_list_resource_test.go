@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccDomainNameServersListResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		Steps: []resource.TestStep{
+			// Query every domain in the account; the test domain must be among them
+			{
+				Query: true,
+				Config: `
+provider "porkbun" {}
+
+list "porkbun_domain_nameservers" "test" {
+  provider = porkbun
+}
+`,
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLengthAtLeast("porkbun_domain_nameservers.test", 1),
+					querycheck.ExpectIdentity("porkbun_domain_nameservers.test", map[string]knownvalue.Check{
+						"domain": knownvalue.StringExact(testDomain),
+					}),
+				},
+			},
+		},
+	})
+}
@@ -2,20 +2,63 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &DomainNameServersResource{}
 var _ resource.ResourceWithImportState = &DomainNameServersResource{}
+var _ resource.ResourceWithIdentity = &DomainNameServersResource{}
+
+// defaultDomainNameServersTimeout applies to create, update and delete when
+// the timeouts block does not set one.
+const defaultDomainNameServersTimeout = 20 * time.Minute
+
+// Defaults for the wait_for_delegation block.
+const (
+	defaultDelegationInterval = 15 * time.Second
+	defaultDelegationTimeout  = 15 * time.Minute
+)
+
+// Values of the on_destroy attribute.
+const (
+	onDestroyResetToPorkbun  = "reset_to_porkbun"
+	onDestroyRetain          = "retain"
+	onDestroyRestoreOriginal = "restore_original"
+)
+
+// originalNameServersKey is the private state key holding the name servers
+// the domain had before Terraform managed it.
+const originalNameServersKey = "original_nameservers"
+
+// porkbunNameServers are Porkbun's default name servers.
+var porkbunNameServers = []string{
+	"curitiba.ns.porkbun.com",
+	"fortaleza.ns.porkbun.com",
+	"maceio.ns.porkbun.com",
+	"salvador.ns.porkbun.com",
+}
 
 func NewDomainNameServersResource() resource.Resource {
 	return &DomainNameServersResource{}
@@ -23,14 +66,30 @@ func NewDomainNameServersResource() resource.Resource {
 
 // DomainNameServersResource defines the resource implementation.
 type DomainNameServersResource struct {
-	client *Client
+	client *porkbun.Client
+	dns    *dnsQuerier
 }
 
 // DomainNameServersResourceModel describes the resource data model.
 type DomainNameServersResourceModel struct {
-	ID          types.String   `tfsdk:"id"`
-	Domain      types.String   `tfsdk:"domain"`
-	NameServers types.Set      `tfsdk:"nameservers"`
+	ID                types.String            `tfsdk:"id"`
+	Domain            IDNStringValue          `tfsdk:"domain"`
+	NameServers       types.Set               `tfsdk:"nameservers"`
+	OnDestroy         types.String            `tfsdk:"on_destroy"`
+	SkipPreflight     types.Bool              `tfsdk:"skip_preflight"`
+	WaitForDelegation *WaitForDelegationModel `tfsdk:"wait_for_delegation"`
+	Timeouts          timeouts.Value          `tfsdk:"timeouts"`
+}
+
+// WaitForDelegationModel describes the wait_for_delegation block.
+type WaitForDelegationModel struct {
+	Interval types.String `tfsdk:"interval"`
+	Timeout  types.String `tfsdk:"timeout"`
+}
+
+// DomainNameServersResourceIdentityModel describes the resource identity data model.
+type DomainNameServersResourceIdentityModel struct {
+	Domain types.String `tfsdk:"domain"`
 }
 
 func (r *DomainNameServersResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -49,10 +108,14 @@ func (r *DomainNameServersResource) Schema(ctx context.Context, req resource.Sch
 				},
 			},
 			"domain": schema.StringAttribute{
-				Description: "The domain name to configure name servers for (e.g., example.com).",
+				Description: "The domain name to configure name servers for (e.g., example.com). Internationalized names may be written in Unicode.",
 				Required:    true,
+				CustomType:  IDNStringType{},
+				Validators: []validator.String{
+					validIDN(),
+				},
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					requiresReplaceUnlessIDNEqual(),
 				},
 			},
 			"nameservers": schema.SetAttribute{
@@ -60,6 +123,63 @@ func (r *DomainNameServersResource) Schema(ctx context.Context, req resource.Sch
 				Required:    true,
 				ElementType: types.StringType,
 			},
+			"on_destroy": schema.StringAttribute{
+				Description: "What to do with the domain's name servers when this resource is destroyed: " +
+					"reset_to_porkbun (the default) points the domain back at Porkbun's name servers, " +
+					"retain leaves the configured name servers in place, and " +
+					"restore_original restores the name servers the domain had before Terraform managed it.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(onDestroyResetToPorkbun),
+				Validators: []validator.String{
+					stringvalidator.OneOf(onDestroyResetToPorkbun, onDestroyRetain, onDestroyRestoreOriginal),
+				},
+			},
+			"skip_preflight": schema.BoolAttribute{
+				Description: "Skip checking that every new name server answers authoritatively for the domain's SOA and NS records " +
+					"before switching to them. Defaults to false.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"wait_for_delegation": schema.SingleNestedBlock{
+				Description: "Wait after changing the name servers until every server of the parent zone (such as the TLD) " +
+					"delegates the domain to them, so that later resources in the same apply see the new delegation.",
+				Attributes: map[string]schema.Attribute{
+					"interval": schema.StringAttribute{
+						Description: "How long to wait between checks, as a duration such as \"30s\". Defaults to \"15s\".",
+						Optional:    true,
+						Validators: []validator.String{
+							positiveDuration(),
+						},
+					},
+					"timeout": schema.StringAttribute{
+						Description: "How long to wait for the delegation before failing, as a duration such as \"30m\". Defaults to \"15m\".",
+						Optional:    true,
+						Validators: []validator.String{
+							positiveDuration(),
+						},
+					},
+				},
+			},
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *DomainNameServersResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"domain": identityschema.StringAttribute{
+				Description:       "The domain name to configure name servers for (e.g., example.com).",
+				RequiredForImport: true,
+			},
 		},
 	}
 }
@@ -70,17 +190,18 @@ func (r *DomainNameServersResource) Configure(ctx context.Context, req resource.
 		return
 	}
 
-	client, ok := req.ProviderData.(*Client)
+	data, ok := req.ProviderData.(*providerData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	r.client = client
+	r.client = data.client
+	r.dns = data.dns
 }
 
 func (r *DomainNameServersResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -93,6 +214,15 @@ func (r *DomainNameServersResource) Create(ctx context.Context, req resource.Cre
 		return
 	}
 
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultDomainNameServersTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	// Convert nameservers set to []string
 	var nsElements []types.String
 	resp.Diagnostics.Append(data.NameServers.ElementsAs(ctx, &nsElements, false)...)
@@ -102,29 +232,57 @@ func (r *DomainNameServersResource) Create(ctx context.Context, req resource.Cre
 
 	nameservers := make([]string, len(nsElements))
 	for i, ns := range nsElements {
-		nameservers[i] = ns.ValueString()
+		nameservers[i] = mustIDNToASCII(ns.ValueString())
 	}
 	sort.Strings(nameservers)
 
+	resp.Diagnostics.Append(r.preflight(ctx, data, nameservers)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Remember the name servers in place before Terraform took over, so
+	// that on_destroy = "restore_original" can put them back.
+	original, err := r.client.GetNameServers(ctx, data.Domain.ValueASCII())
+	if err != nil {
+		addClientError(&resp.Diagnostics, "Unable to read current name servers", err)
+		return
+	}
+	resp.Diagnostics.Append(setOriginalNameServers(ctx, resp.Private, original)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Debug(ctx, "Updating domain name servers", map[string]interface{}{
-		"domain":      data.Domain.ValueString(),
+		"domain":      data.Domain.ValueASCII(),
 		"nameservers": nameservers,
+		"original":    original,
 	})
 
-	err := r.client.UpdateNameServers(data.Domain.ValueString(), nameservers)
+	err = r.client.UpdateNameServers(ctx, data.Domain.ValueASCII(), nameservers)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update name servers: %s", err))
+		addClientError(&resp.Diagnostics, "Unable to update name servers", err)
 		return
 	}
 
-	data.ID = data.Domain
+	data.ID = types.StringValue(data.Domain.ValueString())
 
 	tflog.Trace(ctx, "Updated domain name servers", map[string]interface{}{
-		"domain": data.Domain.ValueString(),
+		"domain": data.Domain.ValueASCII(),
 	})
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+
+	identity := DomainNameServersResourceIdentityModel{
+		Domain: types.StringValue(data.Domain.ValueASCII()),
+	}
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, identity)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.waitForDelegation(ctx, data, nameservers)...)
 }
 
 func (r *DomainNameServersResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -137,18 +295,13 @@ func (r *DomainNameServersResource) Read(ctx context.Context, req resource.ReadR
 		return
 	}
 
-	nameservers, err := r.client.GetNameServers(data.Domain.ValueString())
+	nameservers, err := r.client.GetNameServers(ctx, data.Domain.ValueASCII())
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read name servers: %s", err))
 		return
 	}
 
-	// Convert to types.Set
-	nsValues := make([]types.String, len(nameservers))
-	for i, ns := range nameservers {
-		nsValues[i] = types.StringValue(ns)
-	}
-	nsSet, diags := types.SetValueFrom(ctx, types.StringType, nsValues)
+	nsSet, diags := nameServersValue(ctx, data.NameServers, nameservers)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -157,6 +310,11 @@ func (r *DomainNameServersResource) Read(ctx context.Context, req resource.ReadR
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+
+	identity := DomainNameServersResourceIdentityModel{
+		Domain: types.StringValue(data.Domain.ValueASCII()),
+	}
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, identity)...)
 }
 
 func (r *DomainNameServersResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -169,6 +327,15 @@ func (r *DomainNameServersResource) Update(ctx context.Context, req resource.Upd
 		return
 	}
 
+	updateTimeout, diags := data.Timeouts.Update(ctx, defaultDomainNameServersTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	// Convert nameservers set to []string
 	var nsElements []types.String
 	resp.Diagnostics.Append(data.NameServers.ElementsAs(ctx, &nsElements, false)...)
@@ -178,23 +345,43 @@ func (r *DomainNameServersResource) Update(ctx context.Context, req resource.Upd
 
 	nameservers := make([]string, len(nsElements))
 	for i, ns := range nsElements {
-		nameservers[i] = ns.ValueString()
+		nameservers[i] = mustIDNToASCII(ns.ValueString())
 	}
 	sort.Strings(nameservers)
 
+	var state DomainNameServersResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.NameServers.Equal(state.NameServers) {
+		resp.Diagnostics.Append(r.preflight(ctx, data, nameservers)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	tflog.Debug(ctx, "Updating domain name servers", map[string]interface{}{
-		"domain":      data.Domain.ValueString(),
+		"domain":      data.Domain.ValueASCII(),
 		"nameservers": nameservers,
 	})
 
-	err := r.client.UpdateNameServers(data.Domain.ValueString(), nameservers)
+	err := r.client.UpdateNameServers(ctx, data.Domain.ValueASCII(), nameservers)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update name servers: %s", err))
+		addClientError(&resp.Diagnostics, "Unable to update name servers", err)
 		return
 	}
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.NameServers.Equal(state.NameServers) {
+		resp.Diagnostics.Append(r.waitForDelegation(ctx, data, nameservers)...)
+	}
 }
 
 func (r *DomainNameServersResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -207,52 +394,230 @@ func (r *DomainNameServersResource) Delete(ctx context.Context, req resource.Del
 		return
 	}
 
-	tflog.Debug(ctx, "Resetting domain name servers to Porkbun defaults", map[string]interface{}{
-		"domain": data.Domain.ValueString(),
-	})
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, defaultDomainNameServersTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	var nameservers []string
+
+	switch data.OnDestroy.ValueString() {
+	case onDestroyRetain:
+		tflog.Debug(ctx, "Leaving domain name servers in place", map[string]interface{}{
+			"domain": data.Domain.ValueASCII(),
+		})
+		return
 
-	// Reset to Porkbun's default name servers on delete
-	defaultNS := []string{
-		"curitiba.ns.porkbun.com",
-		"fortaleza.ns.porkbun.com",
-		"maceio.ns.porkbun.com",
-		"salvador.ns.porkbun.com",
+	case onDestroyRestoreOriginal:
+		original, diags := getOriginalNameServers(ctx, req.Private)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if len(original) == 0 {
+			resp.Diagnostics.AddError(
+				"Original Name Servers Unknown",
+				fmt.Sprintf("No original name servers were recorded for %s, so they cannot be restored. "+
+					"This happens for resources created before on_destroy was available. "+
+					"Set on_destroy to %q or %q and apply before destroying.",
+					data.Domain.ValueASCII(), onDestroyResetToPorkbun, onDestroyRetain),
+			)
+			return
+		}
+
+		tflog.Debug(ctx, "Restoring original domain name servers", map[string]interface{}{
+			"domain":      data.Domain.ValueASCII(),
+			"nameservers": original,
+		})
+		nameservers = original
+
+	default:
+		tflog.Debug(ctx, "Resetting domain name servers to Porkbun defaults", map[string]interface{}{
+			"domain": data.Domain.ValueASCII(),
+		})
+		nameservers = porkbunNameServers
 	}
 
-	err := r.client.UpdateNameServers(data.Domain.ValueString(), defaultNS)
+	err := r.client.UpdateNameServers(ctx, data.Domain.ValueASCII(), nameservers)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to reset name servers: %s", err))
+		addClientError(&resp.Diagnostics, "Unable to reset name servers", err)
 		return
 	}
 }
 
+// preflight checks that the new name servers serve the domain's zone before
+// the domain is switched over to them, unless skip_preflight is set.
+func (r *DomainNameServersResource) preflight(ctx context.Context, data DomainNameServersResourceModel, nameservers []string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if data.SkipPreflight.ValueBool() {
+		return diags
+	}
+
+	domain := data.Domain.ValueASCII()
+
+	tflog.Debug(ctx, "Checking new name servers serve the domain", map[string]interface{}{
+		"domain":      domain,
+		"nameservers": nameservers,
+	})
+
+	problems := r.dns.preflightNameServers(ctx, domain, nameservers)
+	if len(problems) > 0 {
+		diags.AddError(
+			"Name Server Pre-flight Check Failed",
+			fmt.Sprintf("Not switching %s to the new name servers, because switching now would take the domain offline:\n\n  - %s\n\n"+
+				"Set up the zone on these name servers first, or set skip_preflight = true to switch anyway.",
+				domain, strings.Join(problems, "\n  - ")),
+		)
+	}
+
+	return diags
+}
+
+// waitForDelegation waits, if the wait_for_delegation block is set, until the
+// parent zone delegates the domain to the new name servers.
+func (r *DomainNameServersResource) waitForDelegation(ctx context.Context, data DomainNameServersResourceModel, nameservers []string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if data.WaitForDelegation == nil {
+		return diags
+	}
+
+	interval := defaultDelegationInterval
+	if v := data.WaitForDelegation.Interval; !v.IsNull() {
+		interval, _ = time.ParseDuration(v.ValueString())
+	}
+	timeout := defaultDelegationTimeout
+	if v := data.WaitForDelegation.Timeout; !v.IsNull() {
+		timeout, _ = time.ParseDuration(v.ValueString())
+	}
+
+	domain := data.Domain.ValueASCII()
+
+	tflog.Debug(ctx, "Waiting for delegation", map[string]interface{}{
+		"domain":      domain,
+		"nameservers": nameservers,
+		"interval":    interval.String(),
+		"timeout":     timeout.String(),
+	})
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// The name servers have been changed by now, so failing would taint
+	// the resource, and the next apply would replace it, switching the
+	// domain back to the default name servers on the way
+	if err := r.dns.waitForDelegation(ctx, domain, nameservers, interval); err != nil {
+		diags.AddWarning(
+			"Delegation Not Propagated",
+			fmt.Sprintf("The name servers of %s were updated, but the parent zone did not delegate to them in time: %s", domain, err),
+		)
+	}
+
+	return diags
+}
+
 func (r *DomainNameServersResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Import format: domain
+	// Import format: domain, or an identity with the domain attribute
 	domain := req.ID
+	if domain == "" {
+		var identity DomainNameServersResourceIdentityModel
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		domain = identity.Domain.ValueString()
+	}
 
 	// Fetch the current nameservers
-	nameservers, err := r.client.GetNameServers(domain)
+	nameservers, err := r.client.GetNameServers(ctx, mustIDNToASCII(domain))
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read name servers: %s", err))
 		return
 	}
 
-	// Convert to types.Set
-	nsValues := make([]types.String, len(nameservers))
-	for i, ns := range nameservers {
-		nsValues[i] = types.StringValue(ns)
-	}
-	nsSet, diags := types.SetValueFrom(ctx, types.StringType, nsValues)
+	nsSet, diags := nameServersValue(ctx, types.SetNull(types.StringType), nameservers)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	data := DomainNameServersResourceModel{
-		ID:          types.StringValue(domain),
-		Domain:      types.StringValue(domain),
-		NameServers: nsSet,
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), domain)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("domain"), domain)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("nameservers"), nsSet)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("on_destroy"), onDestroyResetToPorkbun)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("skip_preflight"), false)...)
+
+	// The name servers found at import time are the ones to restore
+	resp.Diagnostics.Append(setOriginalNameServers(ctx, resp.Private, nameservers)...)
+
+	identity := DomainNameServersResourceIdentityModel{
+		Domain: types.StringValue(mustIDNToASCII(domain)),
 	}
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, identity)...)
+}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+// nameServersValue returns the nameservers attribute for the name servers
+// the API returned. A name server that prior holds in another form, such as
+// in Unicode where the API returns punycode, keeps the form in prior, so
+// that it does not show as changed.
+func nameServersValue(ctx context.Context, prior types.Set, nameservers []string) (types.Set, diag.Diagnostics) {
+	var priorValues []string
+	if !prior.IsNull() && !prior.IsUnknown() {
+		if diags := prior.ElementsAs(ctx, &priorValues, false); diags.HasError() {
+			return types.SetNull(types.StringType), diags
+		}
+	}
+
+	values := make([]string, len(nameservers))
+	for i, ns := range nameservers {
+		values[i] = ns
+		for _, p := range priorValues {
+			if idnEqual(p, ns) {
+				values[i] = p
+				break
+			}
+		}
+	}
+
+	return types.SetValueFrom(ctx, types.StringType, values)
+}
+
+// privateState is the part of the framework's private state API used to
+// store the original name servers.
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// setOriginalNameServers records the domain's name servers from before
+// Terraform managed it.
+func setOriginalNameServers(ctx context.Context, private privateState, nameservers []string) diag.Diagnostics {
+	value, err := json.Marshal(nameservers)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Unable to Store Original Name Servers", err.Error())
+		return diags
+	}
+	return private.SetKey(ctx, originalNameServersKey, value)
+}
+
+// getOriginalNameServers returns the name servers recorded by
+// setOriginalNameServers, or nil if none were recorded.
+func getOriginalNameServers(ctx context.Context, private privateState) ([]string, diag.Diagnostics) {
+	value, diags := private.GetKey(ctx, originalNameServersKey)
+	if diags.HasError() || len(value) == 0 {
+		return nil, diags
+	}
+
+	var nameservers []string
+	if err := json.Unmarshal(value, &nameservers); err != nil {
+		diags.AddError("Unable to Read Original Name Servers", err.Error())
+		return nil, diags
+	}
+	return nameservers, diags
 }
@@ -1,11 +1,21 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
 )
 
 func TestAccDomainNameServersResource(t *testing.T) {
@@ -56,6 +66,121 @@ func TestAccDomainNameServersResource(t *testing.T) {
 	})
 }
 
+func TestAccDomainNameServersResource_Identity(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_12_0),
+		},
+		Steps: []resource.TestStep{
+			// Create and check identity
+			{
+				Config: testAccDomainNameServersResourceConfig(testDomain, []string{
+					"curitiba.ns.porkbun.com",
+					"fortaleza.ns.porkbun.com",
+					"maceio.ns.porkbun.com",
+					"salvador.ns.porkbun.com",
+				}),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectIdentity("porkbun_domain_nameservers.test", map[string]knownvalue.Check{
+						"domain": knownvalue.StringExact(testDomain),
+					}),
+				},
+			},
+			// Import with an import block keyed by identity
+			{
+				ResourceName:    "porkbun_domain_nameservers.test",
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithResourceIdentity,
+			},
+		},
+	})
+}
+
+func TestAccDomainNameServersResource_OnDestroyRetain(t *testing.T) {
+	nameservers := []string{
+		"curitiba.ns.porkbun.com",
+		"fortaleza.ns.porkbun.com",
+		"maceio.ns.porkbun.com",
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			client := porkbun.NewClient(os.Getenv("PORKBUN_API_KEY"), os.Getenv("PORKBUN_SECRET_API_KEY"))
+			current, err := client.GetNameServers(context.Background(), testDomain)
+			if err != nil {
+				return err
+			}
+			if len(current) != len(nameservers) {
+				return fmt.Errorf("expected name servers %v to be retained, got %v", nameservers, current)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDomainNameServersResourceConfigOnDestroy(testDomain, nameservers, "retain"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_domain_nameservers.test", "on_destroy", "retain"),
+					resource.TestCheckResourceAttr("porkbun_domain_nameservers.test", "nameservers.#", "3"),
+				),
+			},
+		},
+	})
+}
+
+// mapPrivateState is an in-memory privateState for unit tests.
+type mapPrivateState map[string][]byte
+
+func (m mapPrivateState) GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics) {
+	return m[key], nil
+}
+
+func (m mapPrivateState) SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics {
+	m[key] = value
+	return nil
+}
+
+func TestOriginalNameServers(t *testing.T) {
+	ctx := context.Background()
+	private := mapPrivateState{}
+
+	got, diags := getOriginalNameServers(ctx, private)
+	if diags.HasError() || got != nil {
+		t.Fatalf("expected no original name servers before any were set, got %v (%v)", got, diags)
+	}
+
+	want := []string{"ns1.example.net", "ns2.example.net"}
+	if diags := setOriginalNameServers(ctx, private, want); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	got, diags = getOriginalNameServers(ctx, private)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func testAccDomainNameServersResourceConfigOnDestroy(domain string, nameservers []string, onDestroy string) string {
+	quoted := make([]string, len(nameservers))
+	for i, ns := range nameservers {
+		quoted[i] = fmt.Sprintf("%q", ns)
+	}
+
+	return fmt.Sprintf(`
+resource "porkbun_domain_nameservers" "test" {
+  domain      = %q
+  nameservers = [%s]
+  on_destroy  = %q
+}
+`, domain, strings.Join(quoted, ", "), onDestroy)
+}
+
 func testAccDomainNameServersResourceConfig(domain string, nameservers []string) string {
 	nsStr := ""
 	for _, ns := range nameservers {
@@ -70,3 +195,63 @@ resource "porkbun_domain_nameservers" "test" {
 }
 `, domain, nsStr)
 }
+
+func TestDomainNameServersResource_WaitForDelegationTimeoutWarns(t *testing.T) {
+	port := startTestDNSServer(t, testParentHandler(func() []string {
+		return []string{"curitiba.ns.porkbun.com."}
+	}))
+
+	r := &DomainNameServersResource{dns: testDNSQuerier(t, port)}
+	data := DomainNameServersResourceModel{
+		Domain: NewIDNStringValue("example.com"),
+		WaitForDelegation: &WaitForDelegationModel{
+			Interval: types.StringValue("10ms"),
+			Timeout:  types.StringValue("100ms"),
+		},
+	}
+
+	diags := r.waitForDelegation(context.Background(), data, []string{"ns1.example.net"})
+	if diags.HasError() {
+		t.Fatalf("expected a delegation timeout not to fail the apply, got: %v", diags)
+	}
+	if diags.WarningsCount() != 1 || !strings.Contains(diags[0].Detail(), "did not delegate to them in time") {
+		t.Fatalf("expected a warning about the delegation, got: %v", diags)
+	}
+}
+
+func TestNameServersValue(t *testing.T) {
+	ctx := context.Background()
+	prior, diags := types.SetValueFrom(ctx, types.StringType, []string{"ns1.müller.de", "ns2.example.net"})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	// The API returns internationalized names in punycode
+	got, diags := nameServersValue(ctx, prior, []string{"ns1.xn--mller-kva.de", "ns2.example.net"})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if !got.Equal(prior) {
+		t.Fatalf("expected the configured Unicode name server to be kept, got %s", got)
+	}
+
+	// A name server that changed is read as the API returns it
+	got, diags = nameServersValue(ctx, prior, []string{"ns1.xn--mller-kva.de", "ns3.example.net"})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	want, _ := types.SetValueFrom(ctx, types.StringType, []string{"ns1.müller.de", "ns3.example.net"})
+	if !got.Equal(want) {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+
+	// Without a prior value, as on import, the API's names are used
+	got, diags = nameServersValue(ctx, types.SetNull(types.StringType), []string{"ns1.xn--mller-kva.de"})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	want, _ = types.SetValueFrom(ctx, types.StringType, []string{"ns1.xn--mller-kva.de"})
+	if !got.Equal(want) {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
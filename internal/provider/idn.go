@@ -0,0 +1,205 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"golang.org/x/net/idna"
+)
+
+// idnToASCII converts the internationalized labels of a domain name to
+// punycode, as the Porkbun API expects. Labels that are already ASCII,
+// including ones that are not valid host names such as _dmarc or *, are
+// left as they are.
+func idnToASCII(name string) (string, error) {
+	if isASCII(name) {
+		return name, nil
+	}
+
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		if isASCII(label) {
+			continue
+		}
+		ascii, err := idna.Lookup.ToASCII(label)
+		if err != nil {
+			return "", fmt.Errorf("invalid internationalized domain name %q: %w", name, err)
+		}
+		labels[i] = ascii
+	}
+
+	return strings.Join(labels, "."), nil
+}
+
+// idnEqual reports whether a and b are the same name, one or both of them
+// possibly written in Unicode rather than punycode.
+func idnEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	asciiA, errA := idnToASCII(a)
+	asciiB, errB := idnToASCII(b)
+	return errA == nil && errB == nil && asciiA == asciiB
+}
+
+// mustIDNToASCII is idnToASCII for values that have already been validated,
+// returning name unchanged if it cannot be converted.
+func mustIDNToASCII(name string) string {
+	ascii, err := idnToASCII(name)
+	if err != nil {
+		return name
+	}
+	return ascii
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// Ensure the custom type and value fully satisfy framework interfaces.
+var _ basetypes.StringTypable = IDNStringType{}
+var _ basetypes.StringValuableWithSemanticEquals = IDNStringValue{}
+var _ validator.String = idnValidator{}
+
+// IDNStringType is a string attribute type for values that are, or may
+// contain, domain names. Unicode and punycode forms of the same name are
+// semantically equal, so whichever form the configuration uses is kept in
+// state. Values are not validated, since not every value of an attribute
+// such as content is a name; attributes that always hold names add
+// validIDN.
+type IDNStringType struct {
+	basetypes.StringType
+}
+
+func (t IDNStringType) Equal(o attr.Type) bool {
+	other, ok := o.(IDNStringType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t IDNStringType) String() string {
+	return "IDNStringType"
+}
+
+func (t IDNStringType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return IDNStringValue{StringValue: in}, nil
+}
+
+func (t IDNStringType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	return IDNStringValue{StringValue: stringValue}, nil
+}
+
+func (t IDNStringType) ValueType(ctx context.Context) attr.Value {
+	return IDNStringValue{}
+}
+
+// IDNStringValue is a value of IDNStringType.
+type IDNStringValue struct {
+	basetypes.StringValue
+}
+
+// NewIDNStringValue returns a known IDNStringValue.
+func NewIDNStringValue(value string) IDNStringValue {
+	return IDNStringValue{StringValue: basetypes.NewStringValue(value)}
+}
+
+func (v IDNStringValue) Equal(o attr.Value) bool {
+	other, ok := o.(IDNStringValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v IDNStringValue) Type(ctx context.Context) attr.Type {
+	return IDNStringType{}
+}
+
+// ValueASCII returns the value with internationalized labels converted to
+// punycode, for sending to the API.
+func (v IDNStringValue) ValueASCII() string {
+	return mustIDNToASCII(v.ValueString())
+}
+
+func (v IDNStringValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(IDNStringValue)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T, got %T. Please report this issue to the provider developers.", v, newValuable),
+		)
+		return false, diags
+	}
+
+	return idnEqual(v.ValueString(), newValue.ValueString()), diags
+}
+
+// idnValidator checks that a string is a domain name whose internationalized
+// labels can be converted to punycode.
+type idnValidator struct{}
+
+// validIDN returns a validator that accepts domain names, with
+// internationalized labels in Unicode or punycode.
+func validIDN() validator.String {
+	return idnValidator{}
+}
+
+func (v idnValidator) Description(ctx context.Context) string {
+	return "value must be a domain name, with internationalized labels in Unicode or punycode"
+}
+
+func (v idnValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v idnValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := idnToASCII(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Internationalized Domain Name", err.Error())
+	}
+}
+
+// requiresReplaceUnlessIDNEqual returns a plan modifier that requires
+// replacement when the value changes, except when only the way an
+// internationalized name is written changes.
+func requiresReplaceUnlessIDNEqual() planmodifier.String {
+	return stringplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+			resp.RequiresReplace = !idnEqual(req.StateValue.ValueString(), req.PlanValue.ValueString())
+		},
+		"Changing the value requires replacement, unless only the Unicode or punycode form of the name changes.",
+		"Changing the value requires replacement, unless only the Unicode or punycode form of the name changes.",
+	)
+}
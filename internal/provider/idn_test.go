@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestIDNToASCII(t *testing.T) {
+	cases := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "example.com", want: "example.com"},
+		{name: "müller.de", want: "xn--mller-kva.de"},
+		{name: "Bücher.example.de", want: "xn--bcher-kva.example.de"},
+		{name: "straße.de", want: "xn--strae-oqa.de"},
+		{name: "_dmarc.müller.de", want: "_dmarc.xn--mller-kva.de"},
+		{name: "*.müller.de", want: "*.xn--mller-kva.de"},
+		{name: "xn--mller-kva.de", want: "xn--mller-kva.de"},
+		{name: "müller!.de", wantErr: true},
+		{name: "-müller.de", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		got, err := idnToASCII(tc.name)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected an error, got %q", tc.name, got)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("%q: expected %q, got %q (%v)", tc.name, tc.want, got, err)
+		}
+	}
+}
+
+func TestIDNStringValue_SemanticEquals(t *testing.T) {
+	ctx := context.Background()
+
+	cases := []struct {
+		a, b string
+		want bool
+	}{
+		{a: "müller.de", b: "xn--mller-kva.de", want: true},
+		{a: "MÜLLER.de", b: "xn--mller-kva.de", want: true},
+		{a: "www.example.com", b: "www.example.com", want: true},
+		{a: "www.example.com", b: "WWW.example.com", want: false},
+		{a: "müller.de", b: "mueller.de", want: false},
+		{a: "v=spf1 -all", b: "v=spf1 ~all", want: false},
+	}
+
+	for _, tc := range cases {
+		got, diags := NewIDNStringValue(tc.a).StringSemanticEquals(ctx, NewIDNStringValue(tc.b))
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if got != tc.want {
+			t.Errorf("%q and %q: expected %t, got %t", tc.a, tc.b, tc.want, got)
+		}
+	}
+}
+
+func TestRecordContent_IDN(t *testing.T) {
+	cases := []struct {
+		recordType string
+		state      string
+		api        string
+	}{
+		{recordType: "CNAME", state: "shop.müller.de", api: "shop.xn--mller-kva.de"},
+		{recordType: "MX", state: "mail.müller.de", api: "mail.xn--mller-kva.de"},
+		{recordType: "SRV", state: "5 5060 sip.müller.de", api: "5 5060 sip.xn--mller-kva.de"},
+		{recordType: "HTTPS", state: "1 cdn.müller.de alpn=h2", api: "1 cdn.xn--mller-kva.de alpn=h2"},
+		{recordType: "A", state: "192.0.2.1", api: "192.0.2.1"},
+	}
+
+	for _, tc := range cases {
+		if got := apiContent(tc.recordType, tc.state); got != tc.api {
+			t.Errorf("%s: expected API content %q, got %q", tc.recordType, tc.api, got)
+		}
+		if got := stateContent(tc.recordType, tc.api); got != tc.state {
+			t.Errorf("%s: expected state content %q, got %q", tc.recordType, tc.state, got)
+		}
+	}
+}
+
+func TestValidIDN(t *testing.T) {
+	ctx := context.Background()
+
+	for value, wantError := range map[string]bool{
+		"müller.de":        false,
+		"xn--mller-kva.de": false,
+		"müller!.de":       true,
+	} {
+		var resp validator.StringResponse
+		validIDN().ValidateString(ctx, validator.StringRequest{ConfigValue: types.StringValue(value)}, &resp)
+		if resp.Diagnostics.HasError() != wantError {
+			t.Errorf("%q: expected error %t, got %v", value, wantError, resp.Diagnostics)
+		}
+	}
+}
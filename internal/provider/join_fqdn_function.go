@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &JoinFQDNFunction{}
+
+func NewJoinFQDNFunction() function.Function {
+	return &JoinFQDNFunction{}
+}
+
+// JoinFQDNFunction joins the name and domain of a porkbun_dns_record into a
+// fully-qualified name.
+type JoinFQDNFunction struct{}
+
+func (f *JoinFQDNFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "join_fqdn"
+}
+
+func (f *JoinFQDNFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Join a record name and domain into a fully-qualified name",
+		Description: "Returns the fully-qualified name of a record with the given name in domain, in lowercase punycode, " +
+			"the same as the fqdn attribute of porkbun_dns_record. Like the resource, name may already include the domain.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "name",
+				Description: "The subdomain, or an empty string for the domain itself.",
+				Validators: []function.StringParameterValidator{
+					validRecordName(),
+				},
+			},
+			function.StringParameter{
+				Name:        "domain",
+				Description: "The domain, such as example.com.",
+				Validators: []function.StringParameterValidator{
+					validRecordName(),
+				},
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *JoinFQDNFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var name, domain string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &name, &domain))
+	if resp.Error != nil {
+		return
+	}
+
+	if domain == "" {
+		resp.Error = function.NewArgumentFuncError(1, "Invalid domain: the domain is empty")
+		return
+	}
+
+	domain = mustIDNToASCII(domain)
+	fqdn := recordFQDN(porkbun.Subdomain(mustIDNToASCII(name), domain), domain)
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, strings.ToLower(fqdn)))
+}
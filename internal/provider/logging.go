@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// tflogHandler is a slog.Handler that writes to the provider's log with
+// tflog, so that what the Porkbun client logs shows up alongside it. The
+// context of each log call must be one the framework passed in.
+type tflogHandler struct {
+	prefix string
+	attrs  map[string]interface{}
+}
+
+func (h tflogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return true
+}
+
+func (h tflogHandler) Handle(ctx context.Context, record slog.Record) error {
+	fields := make(map[string]interface{}, len(h.attrs)+record.NumAttrs())
+	for key, value := range h.attrs {
+		fields[key] = value
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		fields[h.prefix+attr.Key] = attr.Value.Resolve().Any()
+		return true
+	})
+
+	switch {
+	case record.Level >= slog.LevelError:
+		tflog.Error(ctx, record.Message, fields)
+	case record.Level >= slog.LevelWarn:
+		tflog.Warn(ctx, record.Message, fields)
+	case record.Level >= slog.LevelInfo:
+		tflog.Info(ctx, record.Message, fields)
+	default:
+		tflog.Debug(ctx, record.Message, fields)
+	}
+
+	return nil
+}
+
+func (h tflogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	merged := make(map[string]interface{}, len(h.attrs)+len(attrs))
+	for key, value := range h.attrs {
+		merged[key] = value
+	}
+	for _, attr := range attrs {
+		merged[h.prefix+attr.Key] = attr.Value.Resolve().Any()
+	}
+	return tflogHandler{prefix: h.prefix, attrs: merged}
+}
+
+func (h tflogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return tflogHandler{prefix: h.prefix + name + ".", attrs: h.attrs}
+}
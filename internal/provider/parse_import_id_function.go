@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &ParseImportIDFunction{}
+
+func NewParseImportIDFunction() function.Function {
+	return &ParseImportIDFunction{}
+}
+
+// ParseImportIDFunction parses a porkbun_dns_record import ID.
+type ParseImportIDFunction struct{}
+
+// parseImportIDResult is the result of the parse_import_id function.
+type parseImportIDResult struct {
+	Domain   types.String `tfsdk:"domain"`
+	RecordID types.String `tfsdk:"record_id"`
+	Name     types.String `tfsdk:"name"`
+	Type     types.String `tfsdk:"type"`
+	Content  types.String `tfsdk:"content"`
+}
+
+func (f *ParseImportIDFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_import_id"
+}
+
+func (f *ParseImportIDFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Parse a porkbun_dns_record import ID",
+		Description: "Returns an object with the domain, record_id, name, type and content of a porkbun_dns_record import ID " +
+			"in the format domain/record_id, domain/name/type or domain/name/type/content. Parts the ID does not have are null; " +
+			"the root of the domain, written as an empty name or @, has an empty name.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "id",
+				Description: "The import ID.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: map[string]attr.Type{
+				"domain":    types.StringType,
+				"record_id": types.StringType,
+				"name":      types.StringType,
+				"type":      types.StringType,
+				"content":   types.StringType,
+			},
+		},
+	}
+}
+
+func (f *ParseImportIDFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var id string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &id))
+	if resp.Error != nil {
+		return
+	}
+
+	importID, err := parseDNSRecordImportID(id)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Invalid import ID: %s", err))
+		return
+	}
+
+	result := parseImportIDResult{
+		Domain:   types.StringValue(importID.Domain),
+		RecordID: types.StringNull(),
+		Name:     types.StringNull(),
+		Type:     types.StringNull(),
+		Content:  types.StringNull(),
+	}
+	if importID.RecordID != "" {
+		result.RecordID = types.StringValue(importID.RecordID)
+	} else {
+		result.Name = types.StringValue(importID.Name)
+		result.Type = types.StringValue(importID.Type)
+	}
+	if importID.HasContent {
+		result.Content = types.StringValue(importID.Content)
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
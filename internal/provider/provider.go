@@ -2,17 +2,29 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 	"os"
+	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/list"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
 )
 
 // Ensure PorkbunProvider satisfies various provider interfaces.
 var _ provider.Provider = &PorkbunProvider{}
+var _ provider.ProviderWithListResources = &PorkbunProvider{}
+var _ provider.ProviderWithFunctions = &PorkbunProvider{}
 
 // PorkbunProvider defines the provider implementation.
 type PorkbunProvider struct {
@@ -21,8 +33,19 @@ type PorkbunProvider struct {
 
 // PorkbunProviderModel describes the provider data model.
 type PorkbunProviderModel struct {
-	APIKey       types.String `tfsdk:"api_key"`
-	SecretAPIKey types.String `tfsdk:"secret_api_key"`
+	APIKey       types.String   `tfsdk:"api_key"`
+	SecretAPIKey types.String   `tfsdk:"secret_api_key"`
+	DNSResolver  types.String   `tfsdk:"dns_resolver"`
+	DNSQueryPort types.Int64    `tfsdk:"dns_query_port"`
+	Defaults     *DefaultsModel `tfsdk:"defaults"`
+}
+
+// providerData is passed from Configure to resources, data sources and list
+// resources.
+type providerData struct {
+	client   *porkbun.Client
+	dns      *dnsQuerier
+	defaults DefaultsModel
 }
 
 func New(version string) func() provider.Provider {
@@ -52,6 +75,51 @@ func (p *PorkbunProvider) Schema(ctx context.Context, req provider.SchemaRequest
 				Optional:    true,
 				Sensitive:   true,
 			},
+			"dns_resolver": schema.StringAttribute{
+				Description: "Address (host:port) of the DNS resolver used to look up name server addresses for DNS checks. " +
+					"Defaults to the system resolver. Can also be set via the PORKBUN_DNS_RESOLVER environment variable.",
+				Optional: true,
+			},
+			"dns_query_port": schema.Int64Attribute{
+				Description: "Port that name servers are queried on for DNS checks. Defaults to 53. " +
+					"Can also be set via the PORKBUN_DNS_QUERY_PORT environment variable.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"defaults": schema.SingleNestedBlock{
+				Description: "Default values for porkbun_dns_record resources that do not set them.",
+				Attributes: map[string]schema.Attribute{
+					"domain": schema.StringAttribute{
+						Description: "The domain of records that do not set domain.",
+						Optional:    true,
+						CustomType:  IDNStringType{},
+						Validators: []validator.String{
+							validIDN(),
+						},
+					},
+					"ttl": schema.StringAttribute{
+						Description: "The time to live in seconds of records that do not set ttl. Defaults to 600.",
+						Optional:    true,
+					},
+					"notes": schema.StringAttribute{
+						Description: "The notes of records that do not set notes.",
+						Optional:    true,
+					},
+					"prio": schema.MapAttribute{
+						Description: "The priority of records that do not set prio, by record type, such as { MX = \"10\" }. " +
+							"Record types not listed default to 0.",
+						Optional:    true,
+						ElementType: types.StringType,
+						Validators: []validator.Map{
+							mapvalidator.KeysAre(stringvalidator.OneOf(dnsRecordTypes...)),
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -98,10 +166,10 @@ func (p *PorkbunProvider) Configure(ctx context.Context, req provider.ConfigureR
 	}
 
 	// Create a new Porkbun client
-	client := NewClient(apiKey, secretAPIKey)
+	client := porkbun.NewClient(apiKey, secretAPIKey, porkbun.WithLogger(slog.New(tflogHandler{})))
 
 	// Test the connection
-	if err := client.Ping(); err != nil {
+	if _, err := client.Ping(ctx); err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Porkbun API Client",
 			"An unexpected error occurred when creating the Porkbun API client. "+
@@ -111,20 +179,80 @@ func (p *PorkbunProvider) Configure(ctx context.Context, req provider.ConfigureR
 		return
 	}
 
+	dnsQuerier, err := configureDNSQuerier(config, os.Getenv)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid DNS Query Port", err.Error())
+		return
+	}
+
+	data := &providerData{
+		client: client,
+		dns:    dnsQuerier,
+	}
+	if config.Defaults != nil {
+		data.defaults = *config.Defaults
+	}
+
 	// Make the client available during DataSource and Resource type Configure methods.
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	resp.DataSourceData = data
+	resp.ResourceData = data
+	resp.ListResourceData = data
+}
+
+// configureDNSQuerier returns the querier for DNS checks, with the resolver
+// and query port from config or, where config leaves them out, the
+// environment.
+func configureDNSQuerier(config PorkbunProviderModel, getenv func(string) string) (*dnsQuerier, error) {
+	resolver := getenv("PORKBUN_DNS_RESOLVER")
+	if !config.DNSResolver.IsNull() {
+		resolver = config.DNSResolver.ValueString()
+	}
+
+	port := getenv("PORKBUN_DNS_QUERY_PORT")
+	if !config.DNSQueryPort.IsNull() {
+		port = strconv.FormatInt(config.DNSQueryPort.ValueInt64(), 10)
+	}
+	if port != "" {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return nil, fmt.Errorf("the DNS query port must be a number from 1 to 65535, got %q", port)
+		}
+	}
+
+	return newDNSQuerier(resolver, port), nil
 }
 
 func (p *PorkbunProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewDNSRecordResource,
 		NewDomainNameServersResource,
+		NewACMEChallengeResource,
+		NewZoneFileRecordsResource,
 	}
 }
 
 func (p *PorkbunProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewDNSRecordDataSource,
+		NewZoneFileDataSource,
+	}
+}
+
+func (p *PorkbunProvider) ListResources(ctx context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		NewDNSRecordListResource,
+		NewDomainNameServersListResource,
+	}
+}
+
+func (p *PorkbunProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewSPFFunction,
+		NewDMARCFunction,
+		NewCAAFunction,
+		NewSRVContentFunction,
+		NewTLSAFromCertificateFunction,
+		NewSplitFQDNFunction,
+		NewJoinFQDNFunction,
+		NewParseImportIDFunction,
 	}
 }
@@ -0,0 +1,221 @@
+package provider
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+// The record types below have structured content. Each can be written to
+// the content string the Porkbun API expects and parsed back from it.
+
+// srvRecord is the content of an SRV record. The priority is kept in the
+// record's prio field rather than in the content.
+type srvRecord struct {
+	Weight int64
+	Port   int64
+	Target string
+}
+
+func (r srvRecord) content() string {
+	return fmt.Sprintf("%d %d %s", r.Weight, r.Port, r.Target)
+}
+
+func (r srvRecord) normalize() srvRecord {
+	r.Target = normalizeHostName(mustIDNToASCII(r.Target))
+	return r
+}
+
+func parseSRVContent(content string) (srvRecord, error) {
+	fields := strings.Fields(content)
+	if len(fields) != 3 {
+		return srvRecord{}, fmt.Errorf("expected \"weight port target\", got %q", content)
+	}
+
+	weight, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return srvRecord{}, fmt.Errorf("invalid weight %q", fields[0])
+	}
+	port, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return srvRecord{}, fmt.Errorf("invalid port %q", fields[1])
+	}
+
+	return srvRecord{Weight: weight, Port: port, Target: fields[2]}, nil
+}
+
+// srvRecordName returns the name of an SRV record for service and proto,
+// such as _sip._tcp, under the subdomain name. The leading underscores may
+// be left out of service and proto.
+func srvRecordName(service, proto, name string) string {
+	label := "_" + strings.TrimPrefix(service, "_") + "._" + strings.TrimPrefix(proto, "_")
+	if name == "" {
+		return label
+	}
+	return label + "." + name
+}
+
+// parseSRVRecordName splits an SRV record name into its service, proto and
+// the subdomain under them. It reports false if name does not start with
+// _service._proto.
+func parseSRVRecordName(name string) (service, proto, rest string, ok bool) {
+	labels := strings.SplitN(name, ".", 3)
+	if len(labels) < 2 || !strings.HasPrefix(labels[0], "_") || !strings.HasPrefix(labels[1], "_") {
+		return "", "", "", false
+	}
+	if len(labels) == 3 {
+		rest = labels[2]
+	}
+	return labels[0][1:], labels[1][1:], rest, true
+}
+
+// caaRecord is the content of a CAA record.
+type caaRecord struct {
+	Flags int64
+	Tag   string
+	Value string
+}
+
+func (r caaRecord) content() string {
+	return fmt.Sprintf("%d %s %s", r.Flags, r.Tag, porkbun.QuoteCharacterString(r.Value))
+}
+
+func (r caaRecord) normalize() caaRecord {
+	r.Tag = strings.ToLower(r.Tag)
+	return r
+}
+
+func parseCAAContent(content string) (caaRecord, error) {
+	fields := strings.SplitN(strings.TrimSpace(content), " ", 3)
+	if len(fields) != 3 {
+		return caaRecord{}, fmt.Errorf("expected \"flags tag value\", got %q", content)
+	}
+
+	flags, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return caaRecord{}, fmt.Errorf("invalid flags %q", fields[0])
+	}
+
+	value := strings.TrimSpace(fields[2])
+	if strs, ok := porkbun.ParseTXTStrings(value); ok {
+		value = strings.Join(strs, "")
+	}
+
+	return caaRecord{Flags: flags, Tag: fields[1], Value: value}, nil
+}
+
+// tlsaRecord is the content of a TLSA record.
+type tlsaRecord struct {
+	Usage        int64
+	Selector     int64
+	MatchingType int64
+	Data         string
+}
+
+func (r tlsaRecord) content() string {
+	return fmt.Sprintf("%d %d %d %s", r.Usage, r.Selector, r.MatchingType, r.Data)
+}
+
+func (r tlsaRecord) normalize() tlsaRecord {
+	r.Data = strings.ToLower(r.Data)
+	return r
+}
+
+func parseTLSAContent(content string) (tlsaRecord, error) {
+	fields := strings.Fields(content)
+	if len(fields) < 4 {
+		return tlsaRecord{}, fmt.Errorf("expected \"usage selector matching_type data\", got %q", content)
+	}
+
+	var nums [3]int64
+	for i, name := range []string{"usage", "selector", "matching type"} {
+		n, err := strconv.ParseInt(fields[i], 10, 64)
+		if err != nil {
+			return tlsaRecord{}, fmt.Errorf("invalid %s %q", name, fields[i])
+		}
+		nums[i] = n
+	}
+
+	// Long certificate data is sometimes split across several fields
+	return tlsaRecord{
+		Usage:        nums[0],
+		Selector:     nums[1],
+		MatchingType: nums[2],
+		Data:         strings.Join(fields[3:], ""),
+	}, nil
+}
+
+// svcbRecord is the content of an SVCB or HTTPS record. A target of "."
+// means the owner name itself. Params without a value, such as
+// no-default-alpn, have an empty value.
+type svcbRecord struct {
+	Priority int64
+	Target   string
+	Params   map[string]string
+}
+
+func (r svcbRecord) content() string {
+	target := r.Target
+	if target == "" {
+		target = "."
+	}
+
+	parts := []string{strconv.FormatInt(r.Priority, 10), target}
+
+	keys := make([]string, 0, len(r.Params))
+	for key := range r.Params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if value := r.Params[key]; value != "" {
+			parts = append(parts, key+"="+value)
+		} else {
+			parts = append(parts, key)
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func (r svcbRecord) normalize() svcbRecord {
+	if r.Target != "." {
+		r.Target = normalizeHostName(mustIDNToASCII(r.Target))
+	}
+	params := make(map[string]string, len(r.Params))
+	for key, value := range r.Params {
+		params[strings.ToLower(key)] = value
+	}
+	r.Params = params
+	return r
+}
+
+func (r svcbRecord) equal(other svcbRecord) bool {
+	a, b := r.normalize(), other.normalize()
+	return a.Priority == b.Priority && a.Target == b.Target && maps.Equal(a.Params, b.Params)
+}
+
+func parseSVCBContent(content string) (svcbRecord, error) {
+	fields := strings.Fields(content)
+	if len(fields) < 2 {
+		return svcbRecord{}, fmt.Errorf("expected \"priority target params...\", got %q", content)
+	}
+
+	priority, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return svcbRecord{}, fmt.Errorf("invalid priority %q", fields[0])
+	}
+
+	params := make(map[string]string)
+	for _, field := range fields[2:] {
+		key, value, _ := strings.Cut(field, "=")
+		params[key] = strings.Trim(value, `"`)
+	}
+
+	return svcbRecord{Priority: priority, Target: fields[1], Params: params}, nil
+}
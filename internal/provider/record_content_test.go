@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestRecordContent_RoundTrip(t *testing.T) {
+	srv := srvRecord{Weight: 5, Port: 5060, Target: "sip.example.com"}
+	if got := srv.content(); got != "5 5060 sip.example.com" {
+		t.Fatalf("unexpected SRV content %q", got)
+	}
+	if parsed, err := parseSRVContent(srv.content()); err != nil || parsed != srv {
+		t.Fatalf("SRV did not round trip: %+v, %v", parsed, err)
+	}
+
+	caa := caaRecord{Flags: 0, Tag: "issue", Value: "letsencrypt.org; validationmethods=dns-01"}
+	if got := caa.content(); got != `0 issue "letsencrypt.org; validationmethods=dns-01"` {
+		t.Fatalf("unexpected CAA content %q", got)
+	}
+	if parsed, err := parseCAAContent(caa.content()); err != nil || parsed != caa {
+		t.Fatalf("CAA did not round trip: %+v, %v", parsed, err)
+	}
+
+	tlsa := tlsaRecord{Usage: 3, Selector: 1, MatchingType: 1, Data: "0c72ac70b745ac19998811b131d662c9ac69dbdbe7cb23e5b514b56664c5d3d6"}
+	if got := tlsa.content(); got != "3 1 1 0c72ac70b745ac19998811b131d662c9ac69dbdbe7cb23e5b514b56664c5d3d6" {
+		t.Fatalf("unexpected TLSA content %q", got)
+	}
+	if parsed, err := parseTLSAContent(tlsa.content()); err != nil || parsed != tlsa {
+		t.Fatalf("TLSA did not round trip: %+v, %v", parsed, err)
+	}
+
+	svcb := svcbRecord{Priority: 1, Params: map[string]string{"port": "8443", "alpn": "h2,h3", "no-default-alpn": ""}}
+	if got := svcb.content(); got != "1 . alpn=h2,h3 no-default-alpn port=8443" {
+		t.Fatalf("unexpected SVCB content %q", got)
+	}
+	if parsed, err := parseSVCBContent(svcb.content()); err != nil || !parsed.equal(svcbRecord{Priority: 1, Target: ".", Params: svcb.Params}) {
+		t.Fatalf("SVCB did not round trip: %+v, %v", parsed, err)
+	}
+}
+
+func TestRecordContent_ParseErrors(t *testing.T) {
+	if _, err := parseSRVContent("10 5060"); err == nil {
+		t.Error("expected an error for SRV content without a target")
+	}
+	if _, err := parseCAAContent("issue letsencrypt.org"); err == nil {
+		t.Error("expected an error for CAA content without flags")
+	}
+	if _, err := parseTLSAContent("3 x 1 abcd"); err == nil {
+		t.Error("expected an error for TLSA content with a bad selector")
+	}
+	if _, err := parseSVCBContent("1"); err == nil {
+		t.Error("expected an error for SVCB content without a target")
+	}
+}
+
+func TestSRVRecordName(t *testing.T) {
+	if got := srvRecordName("sip", "_tcp", ""); got != "_sip._tcp" {
+		t.Fatalf("unexpected name %q", got)
+	}
+	if got := srvRecordName("_sip", "udp", "voice"); got != "_sip._udp.voice" {
+		t.Fatalf("unexpected name %q", got)
+	}
+
+	service, proto, rest, ok := parseSRVRecordName("_sip._udp.voice")
+	if !ok || service != "sip" || proto != "udp" || rest != "voice" {
+		t.Fatalf("unexpected parse (%q, %q, %q, %t)", service, proto, rest, ok)
+	}
+	if _, _, _, ok := parseSRVRecordName("www"); ok {
+		t.Fatal("expected www not to parse as an SRV name")
+	}
+}
+
+func TestDNSRecordResourceModel_ReadRecord(t *testing.T) {
+	ctx := context.Background()
+
+	data := DNSRecordResourceModel{
+		Name: NewIDNStringValue("voice"),
+		SRV: &SRVModel{
+			Service: types.StringValue("_sip"),
+			Proto:   types.StringValue("tcp"),
+			Weight:  types.Int64Value(5),
+			Port:    types.Int64Value(5060),
+			Target:  types.StringValue("sip.example.com"),
+		},
+	}
+
+	// Formatting differences keep the configured values
+	diags := data.readRecord(ctx, "SRV", "_SIP._tcp.voice", "5 5060 SIP.example.com.")
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if data.Name.ValueString() != "voice" || data.SRV.Service.ValueString() != "_sip" || data.SRV.Target.ValueString() != "sip.example.com" {
+		t.Fatalf("expected the configured values to be kept, got name %s and %+v", data.Name, *data.SRV)
+	}
+	if data.Content.ValueString() != "5 5060 sip.example.com" {
+		t.Fatalf("expected content built from the block, got %s", data.Content)
+	}
+
+	// Real changes are read back into the block
+	data.readRecord(ctx, "SRV", "_sip._tcp.voice", "10 5061 sip2.example.com")
+	if data.SRV.Weight.ValueInt64() != 10 || data.SRV.Port.ValueInt64() != 5061 || data.SRV.Target.ValueString() != "sip2.example.com" {
+		t.Fatalf("expected the changed values to be read, got %+v", *data.SRV)
+	}
+
+	// A record whose name no longer fits the block drops it
+	data.readRecord(ctx, "SRV", "voice", "10 5061 sip2.example.com")
+	if data.SRV != nil || data.Content.ValueString() != "10 5061 sip2.example.com" {
+		t.Fatalf("expected the block to be dropped, got %+v and content %s", data.SRV, data.Content)
+	}
+}
+
+func TestDNSRecordResourceModel_FQDN(t *testing.T) {
+	ctx := context.Background()
+
+	data := DNSRecordResourceModel{
+		Domain: NewIDNStringValue("Example.com"),
+		Name:   NewIDNStringValue("WWW.example.com."),
+	}
+	if got := data.recordName(); got != "WWW" {
+		t.Fatalf("expected the domain to be stripped from the name, got %q", got)
+	}
+	if got := data.fqdn().ValueString(); got != "www.example.com" {
+		t.Fatalf("unexpected fqdn %q", got)
+	}
+
+	// The fully-qualified name is kept when the record is read back
+	data.readRecord(ctx, "A", "www", "192.0.2.1")
+	if data.Name.ValueString() != "WWW.example.com." {
+		t.Fatalf("expected the configured name to be kept, got %s", data.Name)
+	}
+	data.readRecord(ctx, "A", "web", "192.0.2.1")
+	if data.Name.ValueString() != "web" {
+		t.Fatalf("expected the changed name to be read, got %s", data.Name)
+	}
+
+	data.SRV = &SRVModel{Service: types.StringValue("sip"), Proto: types.StringValue("tcp")}
+	data.Name = NewIDNStringValue("bücher.example.com")
+	if got := data.fqdn().ValueString(); got != "_sip._tcp.xn--bcher-kva.example.com" {
+		t.Fatalf("unexpected SRV fqdn %q", got)
+	}
+
+	data.Name = IDNStringValue{StringValue: types.StringUnknown()}
+	if !data.fqdn().IsUnknown() {
+		t.Fatalf("expected an unknown fqdn while the name is unknown")
+	}
+}
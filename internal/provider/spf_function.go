@@ -0,0 +1,211 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// maxSPFLookups is the number of terms causing DNS lookups an SPF record may
+// have, per RFC 7208 section 4.6.4.
+const maxSPFLookups = 10
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &SPFFunction{}
+
+func NewSPFFunction() function.Function {
+	return &SPFFunction{}
+}
+
+// SPFFunction builds the content of an SPF TXT record.
+type SPFFunction struct{}
+
+func (f *SPFFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "spf"
+}
+
+func (f *SPFFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Build SPF record content",
+		Description: "Returns the content of an SPF record, such as v=spf1 mx include:_spf.google.com -all, " +
+			"for use with a porkbun_dns_record of type TXT.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name: "mechanisms",
+				Description: "The mechanisms and modifiers, in order, such as mx, ip4:192.0.2.0/24 or include:_spf.google.com. " +
+					"Mechanisms may start with a qualifier (+, -, ~ or ?).",
+				ElementType: types.StringType,
+			},
+			function.StringParameter{
+				Name:        "all",
+				Description: "The final all mechanism: -all, ~all, ?all or +all, or an empty string to leave it out, such as when using redirect.",
+				Validators: []function.StringParameterValidator{
+					stringvalidator.OneOf("-all", "~all", "?all", "+all", ""),
+				},
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *SPFFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var mechanisms []string
+	var all string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &mechanisms, &all))
+	if resp.Error != nil {
+		return
+	}
+
+	content, err := spfContent(mechanisms, all)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Invalid SPF mechanisms: %s", err))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, content))
+}
+
+// spfContent returns the SPF record made of terms followed by all, checking
+// each term and the number of DNS lookups they cause.
+func spfContent(terms []string, all string) (string, error) {
+	parts := []string{"v=spf1"}
+	lookups := 0
+
+	for _, term := range terms {
+		causesLookup, err := checkSPFTerm(term)
+		if err != nil {
+			return "", err
+		}
+		if causesLookup {
+			lookups++
+		}
+		parts = append(parts, term)
+	}
+
+	if lookups > maxSPFLookups {
+		return "", fmt.Errorf("the record causes %d DNS lookups, more than the %d SPF allows", lookups, maxSPFLookups)
+	}
+
+	if all != "" {
+		parts = append(parts, all)
+	}
+
+	return strings.Join(parts, " "), nil
+}
+
+// checkSPFTerm returns an error if term is not a valid SPF mechanism or
+// modifier, and reports whether it causes a DNS lookup.
+func checkSPFTerm(term string) (bool, error) {
+	if term == "" || strings.ContainsAny(term, " \t") {
+		return false, fmt.Errorf("%q is not a single term", term)
+	}
+
+	// Modifiers: name=value
+	if name, value, ok := strings.Cut(term, "="); ok && !strings.ContainsAny(name, ":/") {
+		switch strings.ToLower(name) {
+		case "redirect":
+			return true, requireSPFDomain(term, value)
+		case "exp":
+			return false, requireSPFDomain(term, value)
+		}
+		return false, fmt.Errorf("%q has an unknown modifier %q", term, name)
+	}
+
+	mechanism := strings.TrimLeft(term, "+-~?")
+	if len(term)-len(mechanism) > 1 {
+		return false, fmt.Errorf("%q has more than one qualifier", term)
+	}
+
+	name, arg, hasArg := strings.Cut(mechanism, ":")
+	name, cidr, hasCIDR := strings.Cut(name, "/")
+	if hasArg {
+		arg, cidr, hasCIDR = cutSPFCIDR(arg, cidr, hasCIDR)
+	}
+
+	switch strings.ToLower(name) {
+	case "all":
+		return false, fmt.Errorf("%q: use the all argument for the all mechanism", term)
+	case "include", "exists":
+		if hasCIDR {
+			return false, fmt.Errorf("%q cannot have a prefix length", term)
+		}
+		return true, requireSPFDomain(term, arg)
+	case "a", "mx":
+		if hasArg {
+			if err := requireSPFDomain(term, arg); err != nil {
+				return false, err
+			}
+		}
+		return true, checkSPFCIDR(term, cidr, hasCIDR)
+	case "ptr":
+		if hasCIDR {
+			return false, fmt.Errorf("%q cannot have a prefix length", term)
+		}
+		return true, nil
+	case "ip4", "ip6":
+		addr, err := netip.ParseAddr(arg)
+		if !hasArg || err != nil || addr.Is4() != (strings.ToLower(name) == "ip4") {
+			return false, fmt.Errorf("%q does not have a valid IPv%s address", term, name[2:])
+		}
+		if hasCIDR && !validPrefixLength(cidr, addr.BitLen()) {
+			return false, fmt.Errorf("%q has an invalid prefix length", term)
+		}
+		return false, nil
+	}
+
+	return false, fmt.Errorf("%q has an unknown mechanism %q", term, name)
+}
+
+// cutSPFCIDR splits the prefix length off the argument of a mechanism, such
+// as the /24 in a:example.com/24.
+func cutSPFCIDR(arg, cidr string, hasCIDR bool) (string, string, bool) {
+	if i := strings.Index(arg, "/"); i >= 0 {
+		return arg[:i], arg[i+1:], true
+	}
+	return arg, cidr, hasCIDR
+}
+
+// checkSPFCIDR checks the prefix length of an a or mx mechanism: an IPv4
+// length such as /24, an IPv6 one written as //64, or both.
+func checkSPFCIDR(term, cidr string, hasCIDR bool) error {
+	if !hasCIDR {
+		return nil
+	}
+
+	ip4, ip6, dual := strings.Cut("/"+cidr, "//")
+	ip4 = strings.TrimPrefix(ip4, "/")
+
+	valid := ip4 != "" || dual
+	if ip4 != "" {
+		valid = valid && validPrefixLength(ip4, 32)
+	}
+	if dual {
+		valid = valid && validPrefixLength(ip6, 128)
+	}
+	if !valid {
+		return fmt.Errorf("%q has an invalid prefix length", term)
+	}
+
+	return nil
+}
+
+func validPrefixLength(bits string, max int) bool {
+	n, err := strconv.Atoi(bits)
+	return err == nil && n >= 0 && n <= max
+}
+
+// requireSPFDomain checks that a term has a domain argument. Domains may
+// use SPF macros, such as %{i}, so they are not checked further.
+func requireSPFDomain(term, domain string) error {
+	if domain == "" {
+		return fmt.Errorf("%q is missing a domain", term)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestSPFContent(t *testing.T) {
+	tooMany := make([]string, maxSPFLookups+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("include:spf%d.example.com", i)
+	}
+
+	cases := []struct {
+		terms   []string
+		all     string
+		want    string
+		wantErr string
+	}{
+		{terms: []string{"mx", "include:_spf.google.com"}, all: "-all", want: "v=spf1 mx include:_spf.google.com -all"},
+		{terms: []string{"ip4:192.0.2.0/24", "ip6:2001:db8::/32", "~a:mail.example.com/28//64"}, all: "~all", want: "v=spf1 ip4:192.0.2.0/24 ip6:2001:db8::/32 ~a:mail.example.com/28//64 ~all"},
+		{terms: []string{"a//64", "-ptr", "exists:%{i}._spf.example.com"}, all: "?all", want: "v=spf1 a//64 -ptr exists:%{i}._spf.example.com ?all"},
+		{terms: []string{"redirect=_spf.example.com"}, all: "", want: "v=spf1 redirect=_spf.example.com"},
+		{terms: nil, all: "-all", want: "v=spf1 -all"},
+		{terms: []string{"ip4:2001:db8::1"}, wantErr: "IPv4"},
+		{terms: []string{"ip4:192.0.2.0/33"}, wantErr: "prefix length"},
+		{terms: []string{"include:"}, wantErr: "missing a domain"},
+		{terms: []string{"include:a.example.com/24"}, wantErr: "prefix length"},
+		{terms: []string{"mx/40"}, wantErr: "prefix length"},
+		{terms: []string{"--mx"}, wantErr: "qualifier"},
+		{terms: []string{"-all"}, wantErr: "all argument"},
+		{terms: []string{"mx include:a.example.com"}, wantErr: "single term"},
+		{terms: []string{"spf2.0/pra"}, wantErr: "unknown mechanism"},
+		{terms: []string{"foo=bar"}, wantErr: "unknown modifier"},
+		{terms: tooMany, wantErr: "11 DNS lookups"},
+	}
+
+	for _, tc := range cases {
+		got, err := spfContent(tc.terms, tc.all)
+		if tc.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("%q: expected an error containing %q, got %q (%v)", tc.terms, tc.wantErr, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("%q: expected %q, got %q (%v)", tc.terms, tc.want, got, err)
+		}
+	}
+}
+
+func TestAccSPFFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::porkbun::spf(["mx", "include:_spf.google.com"], "~all")
+}
+`,
+				Check: resource.TestCheckOutput("test", "v=spf1 mx include:_spf.google.com ~all"),
+			},
+		},
+	})
+}
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+	"golang.org/x/net/publicsuffix"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &SplitFQDNFunction{}
+
+func NewSplitFQDNFunction() function.Function {
+	return &SplitFQDNFunction{}
+}
+
+// SplitFQDNFunction splits a fully-qualified name into the domain and name
+// of a porkbun_dns_record.
+type SplitFQDNFunction struct{}
+
+// splitFQDNResult is the result of the split_fqdn function.
+type splitFQDNResult struct {
+	Domain string `tfsdk:"domain"`
+	Name   string `tfsdk:"name"`
+}
+
+func (f *SplitFQDNFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "split_fqdn"
+}
+
+func (f *SplitFQDNFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Split a fully-qualified name into domain and name",
+		Description: "Returns an object with the domain and name attributes of a porkbun_dns_record for a fully-qualified name, " +
+			"such as { domain = \"example.co.uk\", name = \"www\" } for www.example.co.uk. The domain is the registrable domain " +
+			"according to the public suffix list; name is empty for the domain itself.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "fqdn",
+				Description: "The fully-qualified name, with or without a trailing dot. Internationalized names may be written in Unicode.",
+				Validators: []function.StringParameterValidator{
+					validRecordName(),
+				},
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: map[string]attr.Type{
+				"domain": types.StringType,
+				"name":   types.StringType,
+			},
+		},
+	}
+}
+
+func (f *SplitFQDNFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var fqdn string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &fqdn))
+	if resp.Error != nil {
+		return
+	}
+
+	result, err := splitFQDN(fqdn)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Invalid FQDN: %s", err))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// splitFQDN splits fqdn into its registrable domain and the name under it,
+// both written as they are in fqdn.
+func splitFQDN(fqdn string) (splitFQDNResult, error) {
+	fqdn = strings.TrimSuffix(fqdn, ".")
+
+	ascii, err := idnToASCII(fqdn)
+	if err != nil {
+		return splitFQDNResult{}, err
+	}
+
+	registrable, err := publicsuffix.EffectiveTLDPlusOne(strings.ToLower(ascii))
+	if err != nil {
+		return splitFQDNResult{}, fmt.Errorf("%q is not within a registrable domain: %w", fqdn, err)
+	}
+
+	// Take the domain's labels from fqdn, so that it keeps the way they are
+	// written there
+	labels := strings.Split(fqdn, ".")
+	domain := strings.Join(labels[len(labels)-strings.Count(registrable, ".")-1:], ".")
+
+	return splitFQDNResult{
+		Domain: domain,
+		Name:   porkbun.Subdomain(fqdn, domain),
+	}, nil
+}
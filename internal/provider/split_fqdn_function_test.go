@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestSplitFQDN(t *testing.T) {
+	cases := []struct {
+		fqdn    string
+		want    splitFQDNResult
+		wantErr bool
+	}{
+		{fqdn: "www.example.com", want: splitFQDNResult{Domain: "example.com", Name: "www"}},
+		{fqdn: "www.example.com.", want: splitFQDNResult{Domain: "example.com", Name: "www"}},
+		{fqdn: "example.com", want: splitFQDNResult{Domain: "example.com", Name: ""}},
+		{fqdn: "_dmarc.mail.example.co.uk", want: splitFQDNResult{Domain: "example.co.uk", Name: "_dmarc.mail"}},
+		{fqdn: "*.dev.Example.COM", want: splitFQDNResult{Domain: "Example.COM", Name: "*.dev"}},
+		{fqdn: "shop.müller.de", want: splitFQDNResult{Domain: "müller.de", Name: "shop"}},
+		{fqdn: "co.uk", wantErr: true},
+		{fqdn: "com", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		got, err := splitFQDN(tc.fqdn)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected an error, got %+v", tc.fqdn, got)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("%q: expected %+v, got %+v (%v)", tc.fqdn, tc.want, got, err)
+		}
+	}
+}
+
+func TestAccSplitFQDNFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+locals {
+  split = provider::porkbun::split_fqdn("www.example.co.uk.")
+}
+
+output "domain" {
+  value = local.split.domain
+}
+
+output "name" {
+  value = local.split.name
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("domain", "example.co.uk"),
+					resource.TestCheckOutput("name", "www"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &SRVContentFunction{}
+
+func NewSRVContentFunction() function.Function {
+	return &SRVContentFunction{}
+}
+
+// SRVContentFunction builds the content of an SRV record.
+type SRVContentFunction struct{}
+
+func (f *SRVContentFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "srv_content"
+}
+
+func (f *SRVContentFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Build SRV record content",
+		Description: "Returns the content of an SRV record, \"weight port target\", for use with a porkbun_dns_record " +
+			"of type SRV. The priority is not part of the content; set it with the record's prio.",
+		Parameters: []function.Parameter{
+			function.Int64Parameter{
+				Name:        "weight",
+				Description: "The relative weight of records with the same priority.",
+				Validators: []function.Int64ParameterValidator{
+					int64validator.Between(0, 65535),
+				},
+			},
+			function.Int64Parameter{
+				Name:        "port",
+				Description: "The port the service listens on.",
+				Validators: []function.Int64ParameterValidator{
+					int64validator.Between(0, 65535),
+				},
+			},
+			function.StringParameter{
+				Name:        "target",
+				Description: "The host name providing the service, or . if the service is not available.",
+				Validators: []function.StringParameterValidator{
+					validTarget(),
+				},
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *SRVContentFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var record srvRecord
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &record.Weight, &record.Port, &record.Target))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, record.content()))
+}
@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &TLSAFromCertificateFunction{}
+
+func NewTLSAFromCertificateFunction() function.Function {
+	return &TLSAFromCertificateFunction{}
+}
+
+// TLSAFromCertificateFunction builds the content of a TLSA record from a
+// certificate.
+type TLSAFromCertificateFunction struct{}
+
+func (f *TLSAFromCertificateFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "tlsa_from_certificate"
+}
+
+func (f *TLSAFromCertificateFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Build TLSA record content from a certificate",
+		Description: "Returns the content of a TLSA record, \"usage selector matching_type data\", " +
+			"for a PEM encoded certificate, for use with a porkbun_dns_record of type TLSA.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "certificate",
+				Description: "The PEM encoded certificate. Only the first certificate is used.",
+			},
+			function.Int64Parameter{
+				Name:        "usage",
+				Description: "The certificate usage, from 0 (PKIX-TA) to 3 (DANE-EE).",
+				Validators: []function.Int64ParameterValidator{
+					int64validator.Between(0, 255),
+				},
+			},
+			function.Int64Parameter{
+				Name:        "selector",
+				Description: "The selector: 0 for the full certificate, 1 for the public key.",
+				Validators: []function.Int64ParameterValidator{
+					int64validator.OneOf(0, 1),
+				},
+			},
+			function.Int64Parameter{
+				Name:        "matching_type",
+				Description: "The matching type: 0 for exact match, 1 for SHA-256, 2 for SHA-512.",
+				Validators: []function.Int64ParameterValidator{
+					int64validator.OneOf(0, 1, 2),
+				},
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *TLSAFromCertificateFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var certificate string
+	var record tlsaRecord
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &certificate, &record.Usage, &record.Selector, &record.MatchingType))
+	if resp.Error != nil {
+		return
+	}
+
+	cert, err := parseCertificate(certificate)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Invalid certificate: %s", err))
+		return
+	}
+
+	record.Data = tlsaData(cert, record.Selector, record.MatchingType)
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, record.content()))
+}
+
+// parseCertificate parses the first certificate in PEM encoded data.
+func parseCertificate(data string) (*x509.Certificate, error) {
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM encoded certificate found")
+		}
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
+
+// tlsaData returns the hex encoded certificate association data of a TLSA
+// record for cert, with a selector of 0 or 1 and a matching type of 0, 1
+// or 2.
+func tlsaData(cert *x509.Certificate, selector, matchingType int64) string {
+	data := cert.Raw
+	if selector == 1 {
+		data = cert.RawSubjectPublicKeyInfo
+	}
+
+	switch matchingType {
+	case 1:
+		sum := sha256.Sum256(data)
+		data = sum[:]
+	case 2:
+		sum := sha512.Sum512(data)
+		data = sum[:]
+	}
+
+	return hex.EncodeToString(data)
+}
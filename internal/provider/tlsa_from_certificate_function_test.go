@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+// testCertificate returns a self-signed certificate for tests, PEM encoded
+// and parsed.
+func testCertificate(t *testing.T) (string, *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mail.example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), cert
+}
+
+func TestTLSAData(t *testing.T) {
+	_, cert := testCertificate(t)
+
+	if got := tlsaData(cert, 0, 2); len(got) != 128 {
+		t.Errorf("expected a hex encoded SHA-512 digest, got %q", got)
+	}
+	if got := tlsaData(cert, 1, 0); got != hex.EncodeToString(cert.RawSubjectPublicKeyInfo) {
+		t.Errorf("expected the public key, got %q", got)
+	}
+}
+
+func TestAccTLSAFromCertificateFunction(t *testing.T) {
+	certPEM, cert := testCertificate(t)
+	sum := sha256.Sum256(cert.Raw)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+output "test" {
+  value = provider::porkbun::tlsa_from_certificate(%q, 3, 0, 1)
+}
+`, certPEM),
+				Check: resource.TestCheckOutput("test", "3 0 1 "+hex.EncodeToString(sum[:])),
+			},
+		},
+	})
+}
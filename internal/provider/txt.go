@@ -0,0 +1,16 @@
+package provider
+
+import (
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+// readTXTContent returns the content to keep in state for a TXT record read
+// back from the API: prior, if it holds the same value, so that the way the
+// configuration writes the value does not show up as drift, or otherwise the
+// decoded value.
+func readTXTContent(prior, content string) string {
+	if porkbun.SameContent("TXT", prior, content) {
+		return prior
+	}
+	return porkbun.DecodeTXTContent(content)
+}
@@ -0,0 +1,23 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+func TestReadTXTContent(t *testing.T) {
+	value := strings.Repeat("x", 300)
+	stored := porkbun.QuoteTXTStrings(porkbun.SplitTXTValue(value))
+
+	if got := readTXTContent(value, stored); got != value {
+		t.Fatalf("expected the configured value to be kept, got %q", got)
+	}
+	if got := readTXTContent("", stored); got != value {
+		t.Fatalf("expected the stored value to be reassembled, got %q", got)
+	}
+	if got := readTXTContent("other", "changed"); got != "changed" {
+		t.Fatalf("expected a changed value to be read, got %q", got)
+	}
+}
@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// Ensure the validators fully satisfy framework interfaces.
+var _ validator.String = durationValidator{}
+var _ validator.String = recordNameValidator{}
+var _ function.StringParameterValidator = recordNameValidator{}
+
+// durationValidator checks that a string is a positive Go duration, such as
+// "30s" or "10m".
+type durationValidator struct{}
+
+// positiveDuration returns a validator that accepts positive Go durations.
+func positiveDuration() validator.String {
+	return durationValidator{}
+}
+
+func (v durationValidator) Description(ctx context.Context) string {
+	return "value must be a positive duration, such as \"30s\" or \"10m\""
+}
+
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v durationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	d, err := time.ParseDuration(req.ConfigValue.ValueString())
+	if err == nil && d <= 0 {
+		err = fmt.Errorf("duration must be positive")
+	}
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Duration",
+			fmt.Sprintf("Attribute %s %s, got %q: %s", req.Path, v.Description(ctx), req.ConfigValue.ValueString(), err),
+		)
+	}
+}
+
+// recordNameValidator checks that a string is a valid DNS record name, or
+// for targets, a host name or "." for none.
+type recordNameValidator struct {
+	target bool
+}
+
+// validRecordName returns a validator that accepts record names: labels of
+// letters, digits, hyphens and underscores, such as _dmarc, optionally under
+// a leading * wildcard label and followed by a trailing dot. Internationalized
+// labels are checked in their punycode form.
+func validRecordName() recordNameValidator {
+	return recordNameValidator{}
+}
+
+// validTarget returns a validator that accepts the host names records such
+// as SRV point to, and "." for none.
+func validTarget() recordNameValidator {
+	return recordNameValidator{target: true}
+}
+
+func (v recordNameValidator) Description(ctx context.Context) string {
+	if v.target {
+		return "value must be a host name made of labels of letters, digits, hyphens and underscores, or \".\""
+	}
+	return "value must be a DNS name made of labels of letters, digits, hyphens and underscores, optionally starting with a * wildcard label"
+}
+
+func (v recordNameValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v recordNameValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if err := v.check(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Record Name",
+			fmt.Sprintf("Attribute %s %s, got %q: %s", req.Path, v.Description(ctx), req.ConfigValue.ValueString(), err),
+		)
+	}
+}
+
+func (v recordNameValidator) ValidateParameterString(ctx context.Context, req function.StringParameterValidatorRequest, resp *function.StringParameterValidatorResponse) {
+	if req.Value.IsNull() || req.Value.IsUnknown() {
+		return
+	}
+
+	if err := v.check(req.Value.ValueString()); err != nil {
+		resp.Error = function.NewArgumentFuncError(
+			req.ArgumentPosition,
+			fmt.Sprintf("Invalid name: %s, got %q: %s", v.Description(ctx), req.Value.ValueString(), err),
+		)
+	}
+}
+
+func (v recordNameValidator) check(name string) error {
+	if v.target {
+		if name == "." {
+			return nil
+		}
+		if name == "" {
+			return fmt.Errorf("host name is empty")
+		}
+	}
+	return checkRecordName(name)
+}
+
+// checkRecordName returns an error describing why name is not a valid
+// record name, if it is not. The empty name, for the root of the domain, is
+// valid.
+func checkRecordName(name string) error {
+	if name == "" {
+		return nil
+	}
+
+	ascii, err := idnToASCII(name)
+	if err != nil {
+		return err
+	}
+	ascii = strings.TrimSuffix(ascii, ".")
+	if len(ascii) > 253 {
+		return fmt.Errorf("name is longer than 253 characters")
+	}
+
+	for i, label := range strings.Split(ascii, ".") {
+		switch {
+		case label == "":
+			return fmt.Errorf("name has an empty label")
+		case label == "*":
+			if i != 0 {
+				return fmt.Errorf("the * wildcard can only be the leftmost label")
+			}
+			continue
+		case len(label) > 63:
+			return fmt.Errorf("label %q is longer than 63 characters", label)
+		case strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-"):
+			return fmt.Errorf("label %q starts or ends with a hyphen", label)
+		}
+
+		for _, c := range label {
+			if !isLabelChar(c) {
+				return fmt.Errorf("label %q contains %q", label, c)
+			}
+		}
+	}
+
+	return nil
+}
+
+func isLabelChar(c rune) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_'
+}
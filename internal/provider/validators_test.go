@@ -0,0 +1,38 @@
+package provider
+
+import "testing"
+
+func TestCheckRecordName(t *testing.T) {
+	cases := []struct {
+		name    string
+		wantErr bool
+	}{
+		{name: ""},
+		{name: "www"},
+		{name: "www.example.com."},
+		{name: "*"},
+		{name: "*.dev"},
+		{name: "_dmarc"},
+		{name: "selector1._domainkey"},
+		{name: "_sip._tcp"},
+		{name: "bücher"},
+		{name: "www..example", wantErr: true},
+		{name: ".", wantErr: true},
+		{name: "dev.*", wantErr: true},
+		{name: "a*b", wantErr: true},
+		{name: "-www", wantErr: true},
+		{name: "www-", wantErr: true},
+		{name: "www example", wantErr: true},
+		{name: "a123456789012345678901234567890123456789012345678901234567890123", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		err := checkRecordName(tc.name)
+		if tc.wantErr && err == nil {
+			t.Errorf("%q: expected an error", tc.name)
+		}
+		if !tc.wantErr && err != nil {
+			t.Errorf("%q: unexpected error: %s", tc.name, err)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/neenaoffline/terraform-provider-porkbun/internal/zonefile"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+// defaultZoneFileTTL is the $TTL of exported zone files when the data
+// source does not set one: Porkbun's minimum and default record TTL.
+const defaultZoneFileTTL = "600"
+
+// ttlPattern matches a TTL written as a number of seconds.
+var ttlPattern = regexp.MustCompile(`^[0-9]+$`)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ZoneFileDataSource{}
+
+func NewZoneFileDataSource() datasource.DataSource {
+	return &ZoneFileDataSource{}
+}
+
+// ZoneFileDataSource defines the data source implementation.
+type ZoneFileDataSource struct {
+	client *porkbun.Client
+}
+
+// ZoneFileDataSourceModel describes the data source data model.
+type ZoneFileDataSourceModel struct {
+	ID         types.String   `tfsdk:"id"`
+	Domain     IDNStringValue `tfsdk:"domain"`
+	DefaultTTL types.String   `tfsdk:"default_ttl"`
+	Content    types.String   `tfsdk:"content"`
+}
+
+func (d *ZoneFileDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_zone_file"
+}
+
+func (d *ZoneFileDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Exports all DNS records of a domain as a BIND zone file. Records are sorted so that exports " +
+			"of the same records are identical. Porkbun does not expose the SOA record, so the zone file has none.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The domain name.",
+				Computed:    true,
+			},
+			"domain": schema.StringAttribute{
+				Description: "The domain to export (e.g., example.com). Internationalized names may be written in Unicode.",
+				Required:    true,
+				CustomType:  IDNStringType{},
+				Validators: []validator.String{
+					validIDN(),
+				},
+			},
+			"default_ttl": schema.StringAttribute{
+				Description: "The $TTL directive of the zone file. Every exported record has its own TTL; " +
+					"this applies to records added to the file by hand. Defaults to 600.",
+				Optional: true,
+				Computed: true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(ttlPattern, "must be a number of seconds"),
+				},
+			},
+			"content": schema.StringAttribute{
+				Description: "The zone file.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *ZoneFileDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = data.client
+}
+
+func (d *ZoneFileDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ZoneFileDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	domain := data.Domain.ValueASCII()
+
+	tflog.Debug(ctx, "Exporting zone file", map[string]interface{}{
+		"domain": domain,
+	})
+
+	records, err := d.client.RetrieveDNSRecords(ctx, domain)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list DNS records: %s", err))
+		return
+	}
+
+	if data.DefaultTTL.IsNull() {
+		data.DefaultTTL = types.StringValue(defaultZoneFileTTL)
+	}
+
+	data.ID = types.StringValue(data.Domain.ValueString())
+	data.Content = types.StringValue(zonefile.Render(domain, data.DefaultTTL.ValueString(), records))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccZoneFileDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create a record first, then export the zone
+			{
+				Config: testAccZoneFileDataSourceConfig(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.porkbun_zone_file.test", "id", testDomain),
+					resource.TestCheckResourceAttr("data.porkbun_zone_file.test", "default_ttl", "600"),
+					resource.TestMatchResourceAttr("data.porkbun_zone_file.test", "content",
+						regexp.MustCompile(`(?m)^\$ORIGIN `+regexp.QuoteMeta(testDomain)+`\.$`)),
+					resource.TestMatchResourceAttr("data.porkbun_zone_file.test", "content",
+						regexp.MustCompile(`(?m)^tftest-zonefile\t600\tIN\tTXT\t"exported by terraform"$`)),
+				),
+			},
+		},
+	})
+}
+
+func testAccZoneFileDataSourceConfig() string {
+	return fmt.Sprintf(`
+resource "porkbun_dns_record" "test" {
+  domain  = %[1]q
+  name    = "tftest-zonefile"
+  type    = "TXT"
+  content = "exported by terraform"
+}
+
+data "porkbun_zone_file" "test" {
+  domain = porkbun_dns_record.test.domain
+
+  depends_on = [porkbun_dns_record.test]
+}
+`, testDomain)
+}
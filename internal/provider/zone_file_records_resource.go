@@ -0,0 +1,692 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/neenaoffline/terraform-provider-porkbun/internal/zonefile"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ZoneFileRecordsResource{}
+var _ resource.ResourceWithModifyPlan = &ZoneFileRecordsResource{}
+
+// defaultZoneFileRecordsTimeout applies to create, update and delete when
+// the timeouts block does not set one. Each record is a separate API call,
+// so a whole zone takes longer than a single record.
+const defaultZoneFileRecordsTimeout = 30 * time.Minute
+
+// zoneFileRecordAttrTypes are the attributes of an element of the records
+// attribute.
+var zoneFileRecordAttrTypes = map[string]attr.Type{
+	"id":      types.StringType,
+	"name":    types.StringType,
+	"type":    types.StringType,
+	"content": types.StringType,
+	"ttl":     types.StringType,
+	"prio":    types.StringType,
+	"notes":   types.StringType,
+}
+
+func NewZoneFileRecordsResource() resource.Resource {
+	return &ZoneFileRecordsResource{}
+}
+
+// ZoneFileRecordsResource defines the resource implementation.
+type ZoneFileRecordsResource struct {
+	client *porkbun.Client
+}
+
+// ZoneFileRecordsResourceModel describes the resource data model.
+type ZoneFileRecordsResourceModel struct {
+	ID       types.String   `tfsdk:"id"`
+	Domain   IDNStringValue `tfsdk:"domain"`
+	Content  types.String   `tfsdk:"content"`
+	Records  types.List     `tfsdk:"records"`
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// ZoneFileRecordModel describes an element of the records attribute.
+type ZoneFileRecordModel struct {
+	ID      types.String `tfsdk:"id"`
+	Name    types.String `tfsdk:"name"`
+	Type    types.String `tfsdk:"type"`
+	Content types.String `tfsdk:"content"`
+	TTL     types.String `tfsdk:"ttl"`
+	Prio    types.String `tfsdk:"prio"`
+	Notes   types.String `tfsdk:"notes"`
+}
+
+func (r *ZoneFileRecordsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_zone_file_records"
+}
+
+func (r *ZoneFileRecordsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages all DNS records of a domain from a BIND zone file. Records in the domain that are not in the " +
+			"zone file are deleted, except the NS records of the domain itself, which belong to its name servers. " +
+			"Do not manage records of the same domain with porkbun_dns_record as well.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The domain name (used as identifier).",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"domain": schema.StringAttribute{
+				Description: "The domain whose records to manage (e.g., example.com). Internationalized names may be written in Unicode.",
+				Required:    true,
+				CustomType:  IDNStringType{},
+				Validators: []validator.String{
+					validIDN(),
+				},
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceUnlessIDNEqual(),
+				},
+			},
+			"content": schema.StringAttribute{
+				Description: "The zone file, in RFC 1035 format. $ORIGIN starts out as the domain. Records Porkbun cannot hold, " +
+					"such as SOA and PTR records, are skipped with a warning. A comment after a record becomes its notes.",
+				Required: true,
+			},
+			"records": schema.ListNestedAttribute{
+				Description: "The records of the domain, in the order of the zone files exported by porkbun_zone_file.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description: "The record ID.",
+							Computed:    true,
+						},
+						"name": schema.StringAttribute{
+							Description: "The subdomain, or an empty string for the domain itself.",
+							Computed:    true,
+						},
+						"type": schema.StringAttribute{
+							Description: "The record type.",
+							Computed:    true,
+						},
+						"content": schema.StringAttribute{
+							Description: "The answer content for the record.",
+							Computed:    true,
+						},
+						"ttl": schema.StringAttribute{
+							Description: "The time to live in seconds for the record.",
+							Computed:    true,
+						},
+						"prio": schema.StringAttribute{
+							Description: "The priority of MX and SRV records.",
+							Computed:    true,
+						},
+						"notes": schema.StringAttribute{
+							Description: "Notes for the record.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *ZoneFileRecordsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = data.client
+}
+
+func (r *ZoneFileRecordsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var data ZoneFileRecordsResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Domain.IsUnknown() || data.Content.IsUnknown() {
+		data.Records = types.ListUnknown(types.ObjectType{AttrTypes: zoneFileRecordAttrTypes})
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, &data)...)
+		return
+	}
+
+	domain := data.Domain.ValueASCII()
+
+	parsed, warnings, err := zonefile.Parse(data.Content.ValueString(), domain)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("content"),
+			"Invalid Zone File",
+			fmt.Sprintf("Unable to parse the zone file: %s", err),
+		)
+		return
+	}
+	for _, warning := range warnings {
+		resp.Diagnostics.AddAttributeWarning(path.Root("content"), "Zone File Record Skipped", warning)
+	}
+
+	var lowTTL []string
+	desired := make([]porkbun.DNSRecord, len(parsed))
+	for i, record := range parsed {
+		if ttl, _ := strconv.Atoi(record.TTL); ttl < porkbun.MinTTL {
+			lowTTL = append(lowTTL, strconv.Itoa(record.Line))
+			record.TTL = strconv.Itoa(porkbun.MinTTL)
+		}
+		desired[i] = record.DNSRecord
+	}
+	if len(lowTTL) > 0 {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("content"),
+			"TTL Below Minimum",
+			fmt.Sprintf("The records on lines %s have a TTL below Porkbun's minimum of %d seconds, and are given a TTL of %d.",
+				strings.Join(lowTTL, ", "), porkbun.MinTTL, porkbun.MinTTL),
+		)
+	}
+
+	// Records that are already in the domain keep their IDs, and are
+	// changed in place
+	var prior []ZoneFileRecordModel
+	if !req.State.Raw.IsNull() {
+		var state ZoneFileRecordsResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if idnEqual(state.Domain.ValueString(), data.Domain.ValueString()) && !state.Records.IsNull() && !state.Records.IsUnknown() {
+			resp.Diagnostics.Append(state.Records.ElementsAs(ctx, &prior, false)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+	}
+
+	records := planZoneFileRecords(domain, desired, prior)
+
+	list, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: zoneFileRecordAttrTypes}, records)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Records = list
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &data)...)
+}
+
+func (r *ZoneFileRecordsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ZoneFileRecordsResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultZoneFileRecordsTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	data.ID = types.StringValue(data.Domain.ValueString())
+
+	resp.Diagnostics.Append(r.reconcile(ctx, &data)...)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ZoneFileRecordsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ZoneFileRecordsResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var prior []ZoneFileRecordModel
+	if !data.Records.IsNull() {
+		resp.Diagnostics.Append(data.Records.ElementsAs(ctx, &prior, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	domain := data.Domain.ValueASCII()
+
+	current, err := r.client.RetrieveDNSRecords(ctx, domain)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list DNS records: %s", err))
+		return
+	}
+
+	records := readZoneFileRecords(domain, managedZoneRecords(current, domain), prior)
+
+	list, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: zoneFileRecordAttrTypes}, records)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Records = list
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ZoneFileRecordsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data ZoneFileRecordsResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := data.Timeouts.Update(ctx, defaultZoneFileRecordsTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	resp.Diagnostics.Append(r.reconcile(ctx, &data)...)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ZoneFileRecordsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ZoneFileRecordsResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, defaultZoneFileRecordsTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	var records []ZoneFileRecordModel
+	resp.Diagnostics.Append(data.Records.ElementsAs(ctx, &records, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	domain := data.Domain.ValueASCII()
+
+	current, err := r.client.RetrieveDNSRecords(ctx, domain)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "Unable to list DNS records", err)
+		return
+	}
+	exists := make(map[string]bool, len(current))
+	for _, record := range current {
+		exists[record.ID] = true
+	}
+
+	for _, record := range records {
+		id := record.ID.ValueString()
+		if !exists[id] {
+			continue
+		}
+
+		tflog.Debug(ctx, "Deleting DNS record", map[string]interface{}{
+			"id":     id,
+			"domain": domain,
+		})
+
+		if err := r.client.DeleteDNSRecord(ctx, domain, id); err != nil {
+			addClientError(&resp.Diagnostics, "Unable to delete DNS record", err)
+			return
+		}
+	}
+}
+
+// reconcile changes the records of the domain to the planned records:
+// records the plan does not have are deleted, planned records with an ID or
+// a matching record in the domain are edited where they differ, and the rest
+// are created. The records attribute
+// of data is set to the records of the domain afterwards, including when a
+// change fails part way.
+func (r *ZoneFileRecordsResource) reconcile(ctx context.Context, data *ZoneFileRecordsResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var planned []ZoneFileRecordModel
+	diags.Append(data.Records.ElementsAs(ctx, &planned, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	domain := data.Domain.ValueASCII()
+
+	all, err := r.client.RetrieveDNSRecords(ctx, domain)
+	if err != nil {
+		addClientError(&diags, "Unable to list DNS records", err)
+		return diags
+	}
+
+	// The records of the domain, updated as they are changed
+	current := make(map[string]porkbun.DNSRecord)
+	records := make(map[string]ZoneFileRecordModel)
+	for _, record := range managedZoneRecords(all, domain) {
+		current[record.ID] = record
+		records[record.ID] = zoneFileRecordModel(domain, record)
+	}
+	defer func() {
+		data.Records = zoneFileRecordsList(ctx, domain, records, &diags)
+	}()
+
+	keep := make(map[string]bool)
+	for _, record := range planned {
+		if !record.ID.IsUnknown() {
+			keep[record.ID.ValueString()] = true
+		}
+	}
+
+	sorted := sortedZoneRecords(current)
+
+	// Records the plan would create that are in the domain already, such as
+	// when the resource is first created, are taken over rather than
+	// created again
+	for i, record := range planned {
+		if !record.ID.IsUnknown() {
+			continue
+		}
+		for _, existing := range sorted {
+			if keep[existing.ID] || existing.Type != record.Type.ValueString() ||
+				!strings.EqualFold(porkbun.Subdomain(existing.Name, domain), record.Name.ValueString()) ||
+				!porkbun.SameContent(existing.Type, existing.Content, record.Content.ValueString()) {
+				continue
+			}
+			planned[i].ID = types.StringValue(existing.ID)
+			keep[existing.ID] = true
+			break
+		}
+	}
+
+	// Delete first, so that records taking the place of others, such as a
+	// CNAME record replacing an A record, do not conflict with them
+	for _, record := range sorted {
+		if keep[record.ID] {
+			continue
+		}
+
+		tflog.Debug(ctx, "Deleting DNS record", map[string]interface{}{
+			"id":     record.ID,
+			"domain": domain,
+			"name":   record.Name,
+			"type":   record.Type,
+		})
+
+		if err := r.client.DeleteDNSRecord(ctx, domain, record.ID); err != nil {
+			addClientError(&diags, "Unable to delete DNS record", err)
+			return diags
+		}
+		delete(records, record.ID)
+	}
+
+	for _, record := range planned {
+		name := porkbun.Subdomain(record.Name.ValueString(), domain)
+
+		if record.ID.IsUnknown() {
+			tflog.Debug(ctx, "Creating DNS record", map[string]interface{}{
+				"domain": domain,
+				"name":   name,
+				"type":   record.Type.ValueString(),
+			})
+
+			id, err := r.client.CreateDNSRecordWithExisting(ctx, domain, porkbun.CreateDNSRecordRequest{
+				Name:    name,
+				Type:    record.Type.ValueString(),
+				Content: record.Content.ValueString(),
+				TTL:     record.TTL.ValueString(),
+				Prio:    record.Prio.ValueString(),
+				Notes:   record.Notes.ValueString(),
+			}, all)
+			if err != nil {
+				addClientError(&diags, "Unable to create DNS record", err)
+				return diags
+			}
+			all = append(all, porkbun.DNSRecord{ID: id})
+			record.ID = types.StringValue(id)
+			records[id] = record
+			continue
+		}
+
+		existing, ok := current[record.ID.ValueString()]
+		if !ok {
+			diags.AddError(
+				"DNS Record Not Found",
+				fmt.Sprintf("The %s record %s for %s was deleted outside of Terraform during the apply. Apply again to recreate it.",
+					record.Type.ValueString(), record.ID.ValueString(), recordFQDN(name, domain)),
+			)
+			return diags
+		}
+
+		if !sameZoneFileRecord(existing, record) {
+			tflog.Debug(ctx, "Editing DNS record", map[string]interface{}{
+				"id":     existing.ID,
+				"domain": domain,
+				"name":   name,
+				"type":   record.Type.ValueString(),
+			})
+
+			err := r.client.EditDNSRecord(ctx, domain, existing.ID, porkbun.EditDNSRecordRequest{
+				Name:    name,
+				Type:    record.Type.ValueString(),
+				Content: record.Content.ValueString(),
+				TTL:     record.TTL.ValueString(),
+				Prio:    record.Prio.ValueString(),
+				Notes:   record.Notes.ValueString(),
+			})
+			if err != nil {
+				addClientError(&diags, "Unable to edit DNS record", err)
+				return diags
+			}
+		}
+		records[existing.ID] = record
+	}
+
+	return diags
+}
+
+// zoneFileRecordsList returns the records attribute for records of domain.
+func zoneFileRecordsList(ctx context.Context, domain string, records map[string]ZoneFileRecordModel, diags *diag.Diagnostics) types.List {
+	elements := make([]ZoneFileRecordModel, 0, len(records))
+	for _, record := range records {
+		elements = append(elements, record)
+	}
+	sortZoneFileRecordModels(domain, elements)
+
+	list, d := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: zoneFileRecordAttrTypes}, elements)
+	diags.Append(d...)
+	return list
+}
+
+// managedZoneRecords returns the records of domain that a zone file manages:
+// all of them except the NS records of the domain itself.
+func managedZoneRecords(records []porkbun.DNSRecord, domain string) []porkbun.DNSRecord {
+	var managed []porkbun.DNSRecord
+	for _, record := range records {
+		if record.Type == "NS" && porkbun.Subdomain(record.Name, domain) == "" {
+			continue
+		}
+		managed = append(managed, record)
+	}
+	return managed
+}
+
+// planZoneFileRecords returns the planned records for the desired records of
+// a zone file. A desired record that is in prior with the same content, or
+// failing that with the same name and type, takes its ID, and keeps the way
+// prior writes the content if it is only written differently. The others
+// have unknown IDs, to be created.
+func planZoneFileRecords(domain string, desired []porkbun.DNSRecord, prior []ZoneFileRecordModel) []ZoneFileRecordModel {
+	records := make([]ZoneFileRecordModel, len(desired))
+	for i, record := range desired {
+		records[i] = zoneFileRecordModel(domain, record)
+		records[i].ID = types.StringUnknown()
+	}
+
+	used := make([]bool, len(prior))
+	match := func(record *ZoneFileRecordModel, sameContentOnly bool) {
+		for j, p := range prior {
+			if used[j] || !strings.EqualFold(p.Name.ValueString(), record.Name.ValueString()) || p.Type.ValueString() != record.Type.ValueString() {
+				continue
+			}
+			same := porkbun.SameContent(p.Type.ValueString(), p.Content.ValueString(), record.Content.ValueString())
+			if sameContentOnly && !same {
+				continue
+			}
+			used[j] = true
+			record.ID = p.ID
+			if same {
+				record.Content = p.Content
+			}
+			return
+		}
+	}
+	for i := range records {
+		match(&records[i], true)
+	}
+	for i := range records {
+		if records[i].ID.IsUnknown() {
+			match(&records[i], false)
+		}
+	}
+
+	sortZoneFileRecordModels(domain, records)
+	return records
+}
+
+// readZoneFileRecords returns the records to keep in state for the records
+// of a domain read from the API. A record keeps the way prior writes its
+// name and content if only the way they are written differs.
+func readZoneFileRecords(domain string, current []porkbun.DNSRecord, prior []ZoneFileRecordModel) []ZoneFileRecordModel {
+	records := make([]ZoneFileRecordModel, len(current))
+	for i, record := range current {
+		records[i] = zoneFileRecordModel(domain, record)
+		for _, p := range prior {
+			if p.ID.ValueString() != record.ID {
+				continue
+			}
+			if strings.EqualFold(p.Name.ValueString(), records[i].Name.ValueString()) {
+				records[i].Name = p.Name
+			}
+			if porkbun.SameContent(record.Type, p.Content.ValueString(), record.Content) {
+				records[i].Content = p.Content
+			}
+		}
+	}
+
+	sortZoneFileRecordModels(domain, records)
+	return records
+}
+
+// zoneFileRecordModel returns the element of the records attribute for a
+// record of domain.
+func zoneFileRecordModel(domain string, record porkbun.DNSRecord) ZoneFileRecordModel {
+	return ZoneFileRecordModel{
+		ID:      types.StringValue(record.ID),
+		Name:    types.StringValue(strings.ToLower(porkbun.Subdomain(record.Name, domain))),
+		Type:    types.StringValue(record.Type),
+		Content: types.StringValue(record.Content),
+		TTL:     types.StringValue(record.TTL),
+		Prio:    types.StringValue(record.Prio),
+		Notes:   types.StringValue(record.Notes),
+	}
+}
+
+// sameZoneFileRecord reports whether a record read from the API matches a
+// planned record, so that it does not need editing.
+func sameZoneFileRecord(current porkbun.DNSRecord, planned ZoneFileRecordModel) bool {
+	return current.Type == planned.Type.ValueString() &&
+		porkbun.SameContent(current.Type, current.Content, planned.Content.ValueString()) &&
+		current.TTL == planned.TTL.ValueString() &&
+		current.Prio == planned.Prio.ValueString() &&
+		current.Notes == planned.Notes.ValueString()
+}
+
+// sortedZoneRecords returns records in the order of zonefile.Less.
+func sortedZoneRecords(records map[string]porkbun.DNSRecord) []porkbun.DNSRecord {
+	sorted := make([]porkbun.DNSRecord, 0, len(records))
+	for _, record := range records {
+		sorted = append(sorted, record)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return zonefile.Less(sorted[i], sorted[j])
+	})
+	return sorted
+}
+
+// sortZoneFileRecordModels sorts records of domain in the order of
+// zonefile.Less.
+func sortZoneFileRecordModels(domain string, records []ZoneFileRecordModel) {
+	key := func(record ZoneFileRecordModel) porkbun.DNSRecord {
+		return porkbun.DNSRecord{
+			Name:    strings.ToLower(recordFQDN(record.Name.ValueString(), domain)),
+			Type:    record.Type.ValueString(),
+			Content: record.Content.ValueString(),
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return zonefile.Less(key(records[i]), key(records[j]))
+	})
+}
@@ -0,0 +1,260 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/neenaoffline/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+func TestAccZoneFileRecordsResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing; this replaces all records of the test
+			// domain
+			{
+				Config: testAccZoneFileRecordsResourceConfig(`$TTL 600
+@	IN	SOA	ns1.example.net. hostmaster.example.net. ( 1 7200 3600 1209600 600 )
+@	IN	A	192.0.2.1 ; web server
+www	IN	CNAME	@
+@	IN	MX	10 mail
+mail	IN	A	192.0.2.2
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_zone_file_records.test", "id", testDomain),
+					resource.TestCheckResourceAttr("porkbun_zone_file_records.test", "records.#", "4"),
+					resource.TestCheckResourceAttr("porkbun_zone_file_records.test", "records.0.name", ""),
+					resource.TestCheckResourceAttr("porkbun_zone_file_records.test", "records.0.type", "A"),
+					resource.TestCheckResourceAttr("porkbun_zone_file_records.test", "records.0.content", "192.0.2.1"),
+					resource.TestCheckResourceAttr("porkbun_zone_file_records.test", "records.0.notes", "web server"),
+					resource.TestCheckResourceAttr("porkbun_zone_file_records.test", "records.1.type", "MX"),
+					resource.TestCheckResourceAttr("porkbun_zone_file_records.test", "records.1.prio", "10"),
+					resource.TestCheckResourceAttrSet("porkbun_zone_file_records.test", "records.1.id"),
+				),
+			},
+			// Update: change a record in place and remove another
+			{
+				Config: testAccZoneFileRecordsResourceConfig(`$TTL 600
+@	IN	A	192.0.2.10 ; web server
+www	IN	CNAME	@
+@	IN	MX	10 mail
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_zone_file_records.test", "records.#", "3"),
+					resource.TestCheckResourceAttr("porkbun_zone_file_records.test", "records.0.content", "192.0.2.10"),
+					resource.TestCheckResourceAttr("porkbun_zone_file_records.test", "records.2.name", "www"),
+				),
+			},
+		},
+	})
+}
+
+func testAccZoneFileRecordsResourceConfig(zone string) string {
+	return fmt.Sprintf(`
+resource "porkbun_zone_file_records" "test" {
+  domain  = %[1]q
+  content = <<-EOT
+%[2]s
+EOT
+}
+`, testDomain, zone)
+}
+
+func TestPlanZoneFileRecords(t *testing.T) {
+	prior := []ZoneFileRecordModel{
+		zoneFileRecordModel("example.com", porkbun.DNSRecord{ID: "1", Name: "example.com", Type: "A", Content: "192.0.2.1", TTL: "600", Prio: "0"}),
+		zoneFileRecordModel("example.com", porkbun.DNSRecord{ID: "2", Name: "www.example.com", Type: "CNAME", Content: "Example.com.", TTL: "600", Prio: "0"}),
+		zoneFileRecordModel("example.com", porkbun.DNSRecord{ID: "3", Name: "old.example.com", Type: "A", Content: "192.0.2.3", TTL: "600", Prio: "0"}),
+	}
+	desired := []porkbun.DNSRecord{
+		{Name: "www.example.com", Type: "CNAME", Content: "example.com", TTL: "600", Prio: "0"},
+		{Name: "example.com", Type: "A", Content: "192.0.2.10", TTL: "3600", Prio: "0"},
+		{Name: "example.com", Type: "MX", Content: "mail.example.com", TTL: "600", Prio: "10"},
+	}
+
+	records := planZoneFileRecords("example.com", desired, prior)
+
+	want := []ZoneFileRecordModel{
+		// Changed in place
+		{ID: types.StringValue("1"), Name: types.StringValue(""), Type: types.StringValue("A"), Content: types.StringValue("192.0.2.10"),
+			TTL: types.StringValue("3600"), Prio: types.StringValue("0"), Notes: types.StringValue("")},
+		// Created
+		{ID: types.StringUnknown(), Name: types.StringValue(""), Type: types.StringValue("MX"), Content: types.StringValue("mail.example.com"),
+			TTL: types.StringValue("600"), Prio: types.StringValue("10"), Notes: types.StringValue("")},
+		// Unchanged, keeping the content as it was written before
+		{ID: types.StringValue("2"), Name: types.StringValue("www"), Type: types.StringValue("CNAME"), Content: types.StringValue("Example.com."),
+			TTL: types.StringValue("600"), Prio: types.StringValue("0"), Notes: types.StringValue("")},
+	}
+
+	if len(records) != len(want) {
+		t.Fatalf("expected %d records, got %d: %+v", len(want), len(records), records)
+	}
+	for i := range want {
+		if records[i] != want[i] {
+			t.Errorf("record %d: expected %+v, got %+v", i, want[i], records[i])
+		}
+	}
+}
+
+func TestReadZoneFileRecords(t *testing.T) {
+	prior := []ZoneFileRecordModel{
+		zoneFileRecordModel("example.com", porkbun.DNSRecord{ID: "1", Name: "example.com", Type: "TXT", Content: "v=spf1 -all", TTL: "600", Prio: "0"}),
+	}
+	current := managedZoneRecords([]porkbun.DNSRecord{
+		{ID: "1", Name: "example.com", Type: "TXT", Content: `"v=spf1 -all"`, TTL: "600", Prio: "0"},
+		{ID: "2", Name: "example.com", Type: "NS", Content: "curitiba.ns.porkbun.com", TTL: "86400", Prio: "0"},
+		{ID: "3", Name: "ns.example.com", Type: "NS", Content: "ns1.example.net", TTL: "600", Prio: "0"},
+	}, "example.com")
+
+	records := readZoneFileRecords("example.com", current, prior)
+
+	if len(records) != 2 {
+		t.Fatalf("expected the records but the apex NS record, got %+v", records)
+	}
+	if got := records[0].Content.ValueString(); got != "v=spf1 -all" {
+		t.Errorf("expected the TXT content as written before, got %q", got)
+	}
+	if got := records[1].Name.ValueString(); got != "ns" {
+		t.Errorf("expected the delegation to ns, got %q", got)
+	}
+}
+
+// testZoneFileRecordsResource returns a ZoneFileRecordsResource using api,
+// and the paths of the requests made to it. Edits of the record with ID
+// failEdit fail.
+func testZoneFileRecordsResource(t *testing.T, api *porkbuntest.API, failEdit string) (*ZoneFileRecordsResource, *[]string) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/dns/edit/example.com/"+failEdit {
+			fmt.Fprint(w, `{"status":"ERROR","message":"Edit error: We were unable to edit the DNS record."}`)
+			return
+		}
+		api.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return &ZoneFileRecordsResource{client: porkbun.NewClient("pk1_test", "sk1_test", porkbun.WithBaseURL(server.URL))}, &paths
+}
+
+// testZoneFileRecordsModel returns the model of a zone file of example.com
+// with the planned records for desired.
+func testZoneFileRecordsModel(t *testing.T, desired []porkbun.DNSRecord, prior []ZoneFileRecordModel) *ZoneFileRecordsResourceModel {
+	records, diags := types.ListValueFrom(context.Background(), types.ObjectType{AttrTypes: zoneFileRecordAttrTypes},
+		planZoneFileRecords("example.com", desired, prior))
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	return &ZoneFileRecordsResourceModel{Domain: NewIDNStringValue("example.com"), Records: records}
+}
+
+// zoneFileRecordsState returns the records attribute of data, one per line,
+// as ID, name, type, TTL, priority and content.
+func zoneFileRecordsState(t *testing.T, data *ZoneFileRecordsResourceModel) []string {
+	var records []ZoneFileRecordModel
+	if diags := data.Records.ElementsAs(context.Background(), &records, false); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	var lines []string
+	for _, record := range records {
+		lines = append(lines, fmt.Sprintf("%s %s %s %s %s %s", record.ID.ValueString(), record.Name.ValueString(), record.Type.ValueString(),
+			record.TTL.ValueString(), record.Prio.ValueString(), record.Content.ValueString()))
+	}
+	return lines
+}
+
+func TestZoneFileRecordsResource_Reconcile(t *testing.T) {
+	api := &porkbuntest.API{
+		Records: []porkbun.DNSRecord{
+			{ID: "1", Name: "example.com", Type: "NS", Content: "curitiba.ns.porkbun.com", TTL: "86400", Prio: "0"},
+			{ID: "2", Name: "example.com", Type: "A", Content: "192.0.2.1", TTL: "600", Prio: "0"},
+			{ID: "3", Name: "www.example.com", Type: "A", Content: "192.0.2.3", TTL: "600", Prio: "0"},
+		},
+		NextID: 10,
+	}
+	r, paths := testZoneFileRecordsResource(t, api, "")
+
+	// The resource is created over a domain that has some of the records
+	// already, and a CNAME record takes the place of an A record
+	data := testZoneFileRecordsModel(t, []porkbun.DNSRecord{
+		{Name: "example.com", Type: "A", Content: "192.0.2.1", TTL: "600", Prio: "0"},
+		{Name: "www.example.com", Type: "CNAME", Content: "example.com", TTL: "600", Prio: "0"},
+	}, nil)
+
+	if diags := r.reconcile(context.Background(), data); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	wantPaths := []string{
+		"/dns/retrieve/example.com",
+		"/dns/delete/example.com/3",
+		"/dns/create/example.com",
+	}
+	if !slices.Equal(*paths, wantPaths) {
+		t.Errorf("expected the A record to be taken over and the other deleted before the CNAME record is created, got requests %q", *paths)
+	}
+
+	wantAPI := []string{
+		"1 example.com NS 86400 0 curitiba.ns.porkbun.com",
+		"2 example.com A 600 0 192.0.2.1",
+		"11 www.example.com CNAME 600 0 example.com",
+	}
+	if got := api.Summary(); !slices.Equal(got, wantAPI) {
+		t.Errorf("expected the apex NS record to be left alone, got records %q", got)
+	}
+
+	wantState := []string{
+		"2  A 600 0 192.0.2.1",
+		"11 www CNAME 600 0 example.com",
+	}
+	if got := zoneFileRecordsState(t, data); !slices.Equal(got, wantState) {
+		t.Errorf("expected records %q, got %q", wantState, got)
+	}
+}
+
+func TestZoneFileRecordsResource_ReconcilePartialFailure(t *testing.T) {
+	api := &porkbuntest.API{
+		Records: []porkbun.DNSRecord{
+			{ID: "1", Name: "example.com", Type: "A", Content: "192.0.2.1", TTL: "600", Prio: "0"},
+			{ID: "2", Name: "old.example.com", Type: "A", Content: "192.0.2.2", TTL: "600", Prio: "0"},
+			{ID: "3", Name: "www.example.com", Type: "A", Content: "192.0.2.3", TTL: "600", Prio: "0"},
+		},
+		NextID: 10,
+	}
+	r, _ := testZoneFileRecordsResource(t, api, "3")
+
+	var prior []ZoneFileRecordModel
+	for _, record := range api.Records {
+		prior = append(prior, zoneFileRecordModel("example.com", record))
+	}
+	data := testZoneFileRecordsModel(t, []porkbun.DNSRecord{
+		{Name: "example.com", Type: "A", Content: "192.0.2.1", TTL: "3600", Prio: "0"},
+		{Name: "mail.example.com", Type: "A", Content: "192.0.2.4", TTL: "600", Prio: "0"},
+		{Name: "www.example.com", Type: "A", Content: "192.0.2.30", TTL: "600", Prio: "0"},
+	}, prior)
+
+	if diags := r.reconcile(context.Background(), data); !diags.HasError() {
+		t.Fatal("expected the failed edit to be reported")
+	}
+
+	// The state has the changes made before the edit failed, and the record
+	// that failed to be edited as it still is
+	want := []string{
+		"1  A 3600 0 192.0.2.1",
+		"11 mail A 600 0 192.0.2.4",
+		"3 www A 600 0 192.0.2.3",
+	}
+	if got := zoneFileRecordsState(t, data); !slices.Equal(got, want) {
+		t.Errorf("expected records %q, got %q", want, got)
+	}
+}
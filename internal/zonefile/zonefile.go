@@ -0,0 +1,375 @@
+// Package zonefile renders the DNS records of a Porkbun domain as a BIND
+// zone file and parses zone files into records. It is shared by the
+// porkbun_zone_file data source, the porkbun_zone_file_records resource and
+// the zone commands of the porkbun CLI.
+package zonefile
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/miekg/dns"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+// Render renders the records of domain as a BIND zone file, with
+// names relative to $ORIGIN and records in a stable order, so that exports
+// of the same records are identical. ttl is written as the $TTL directive.
+//
+// ALIAS records, which have no standard zone file form, are written as
+// comments.
+func Render(domain, ttl string, records []porkbun.DNSRecord) string {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+
+	sorted := make([]porkbun.DNSRecord, len(records))
+	copy(sorted, records)
+	for i := range sorted {
+		sorted[i].Name = strings.ToLower(strings.TrimSuffix(sorted[i].Name, "."))
+		sorted[i].Content = zoneRecordData(sorted[i])
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return Less(sorted[i], sorted[j])
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "; Zone file for %s, exported from Porkbun\n", domain)
+	fmt.Fprintf(&b, "$ORIGIN %s.\n", domain)
+	fmt.Fprintf(&b, "$TTL %s\n\n", ttl)
+
+	for _, record := range sorted {
+		name := porkbun.Subdomain(record.Name, domain)
+		if name == "" {
+			name = "@"
+		}
+
+		line := fmt.Sprintf("%s\t%s\tIN\t%s\t%s", name, record.TTL, record.Type, record.Content)
+		if record.Type == "ALIAS" {
+			line = "; " + line
+		}
+		if record.Notes != "" {
+			line += " ; " + strings.Join(strings.Fields(record.Notes), " ")
+		}
+		b.WriteString(line + "\n")
+	}
+
+	return b.String()
+}
+
+// zoneRecordData returns the RDATA of record as written in a zone file: host
+// names made absolute, the priority of MX and SRV records put in front, and
+// TXT values split into quoted character-strings.
+func zoneRecordData(record porkbun.DNSRecord) string {
+	content := strings.TrimSpace(record.Content)
+
+	switch record.Type {
+	case "CNAME", "ALIAS", "NS":
+		return absoluteName(content)
+
+	case "MX":
+		return porkbun.PrioOrZero(record.Prio) + " " + absoluteName(content)
+
+	case "SRV":
+		if fields := strings.Fields(content); len(fields) == 3 && isDigits(fields[0]) && isDigits(fields[1]) {
+			fields[2] = absoluteName(fields[2])
+			return porkbun.PrioOrZero(record.Prio) + " " + strings.Join(fields, " ")
+		}
+
+	case "HTTPS", "SVCB":
+		if fields := strings.Fields(content); len(fields) >= 2 {
+			fields[1] = absoluteName(fields[1])
+			return strings.Join(fields, " ")
+		}
+
+	case "CAA":
+		if fields := strings.SplitN(content, " ", 3); len(fields) == 3 && isDigits(fields[0]) {
+			value := strings.TrimSpace(fields[2])
+			if strs, ok := porkbun.ParseTXTStrings(value); ok {
+				value = strings.Join(strs, "")
+			}
+			return fields[0] + " " + fields[1] + " " + porkbun.QuoteCharacterString(value)
+		}
+
+	case "TXT":
+		strs, ok := porkbun.ParseTXTStrings(content)
+		if !ok {
+			strs = []string{content}
+		}
+		var chunks []string
+		for _, s := range strs {
+			chunks = append(chunks, porkbun.SplitTXTValue(s)...)
+		}
+		return porkbun.QuoteTXTStrings(chunks)
+	}
+
+	return content
+}
+
+// absoluteName returns a host name with a trailing dot, so that a zone file
+// does not read it relative to $ORIGIN.
+func absoluteName(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+// Less orders records with lowercase names by name, in canonical
+// order, then by type and content.
+func Less(a, b porkbun.DNSRecord) bool {
+	if c := CompareCanonicalNames(a.Name, b.Name); c != 0 {
+		return c < 0
+	}
+	if a.Type != b.Type {
+		return a.Type < b.Type
+	}
+	return a.Content < b.Content
+}
+
+// CompareCanonicalNames compares two lowercase domain names in the canonical
+// order of RFC 4034 section 6.1: label by label from the right, so that
+// names are grouped under their parents.
+func CompareCanonicalNames(a, b string) int {
+	labelsA := strings.Split(a, ".")
+	labelsB := strings.Split(b, ".")
+
+	for i, j := len(labelsA)-1, len(labelsB)-1; i >= 0 && j >= 0; i, j = i-1, j-1 {
+		if c := strings.Compare(labelsA[i], labelsB[j]); c != 0 {
+			return c
+		}
+	}
+	return len(labelsA) - len(labelsB)
+}
+
+// Record is a record parsed from a zone file, in the form the
+// Porkbun API uses, with the line of the zone file it starts on. Name is
+// fully qualified, in lowercase and without a trailing dot.
+type Record struct {
+	porkbun.DNSRecord
+	Line int
+}
+
+// Lines with ALIAS records, which zone file parsers do not know. The second
+// form is how Render writes them.
+var (
+	aliasLinePattern          = regexp.MustCompile(`(?i)^(\S*[ \t]+(?:(?:[0-9]\S*|IN)[ \t]+){0,2})ALIAS([ \t])`)
+	commentedAliasLinePattern = regexp.MustCompile(`^;[ \t]*(\S+[ \t]+[0-9]+[ \t]+IN[ \t]+)ALIAS([ \t])`)
+)
+
+// Parse parses a zone file for domain into the records Porkbun can
+// hold. $ORIGIN starts out as domain. Records that cannot be represented,
+// such as the SOA record, apex NS records, records outside the domain and
+// record types Porkbun does not support, are skipped with a warning naming
+// their line. A comment after a record becomes its notes.
+func Parse(text, domain string) ([]Record, []string, error) {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+
+	// Read ALIAS records, which the parser does not know, as CNAME records
+	// and change them back afterwards
+	lines := strings.Split(text, "\n")
+	aliasLines := make(map[int]bool)
+	for i, line := range lines {
+		for _, pattern := range []*regexp.Regexp{aliasLinePattern, commentedAliasLinePattern} {
+			if pattern.MatchString(line) {
+				lines[i] = pattern.ReplaceAllString(line, "${1}CNAME${2}")
+				aliasLines[i+1] = true
+				break
+			}
+		}
+	}
+	text = strings.Join(lines, "\n")
+
+	var records []Record
+	var warnings []string
+
+	r := strings.NewReader(text)
+	zp := dns.NewZoneParser(r, domain+".", "")
+
+	// offset is how far the parser has read, and offsetLine the line there
+	offset, offsetLine, line := 0, 1, 1
+	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		// The parser has read up to the end of the record; it starts on the
+		// first line read since the previous one that is not blank, a
+		// comment or a directive. Records made by a $GENERATE directive
+		// after the first one are not read from the text at all.
+		end := len(text) - r.Len()
+		if end > offset {
+			line = zoneRecordLine(text[offset:end], offsetLine)
+			offsetLine += strings.Count(text[offset:end], "\n")
+			offset = end
+		}
+
+		warn := func(format string, args ...interface{}) {
+			warnings = append(warnings, fmt.Sprintf("line %d: ", line)+fmt.Sprintf(format, args...))
+		}
+
+		header := rr.Header()
+		name := strings.ToLower(strings.TrimSuffix(header.Name, "."))
+		recordType := dns.TypeToString[header.Rrtype]
+
+		if header.Class != dns.ClassINET {
+			warn("skipped %s record of class %s; only class IN records can be created", recordType, dns.ClassToString[header.Class])
+			continue
+		}
+		if name != domain && !strings.HasSuffix(name, "."+domain) {
+			warn("skipped %s record for %s, which is outside %s", recordType, name, domain)
+			continue
+		}
+
+		record := Record{
+			DNSRecord: porkbun.DNSRecord{
+				Name:  name,
+				Type:  recordType,
+				TTL:   strconv.FormatUint(uint64(header.Ttl), 10),
+				Prio:  "0",
+				Notes: strings.TrimSpace(strings.TrimPrefix(zp.Comment(), ";")),
+			},
+			Line: line,
+		}
+
+		switch v := rr.(type) {
+		case *dns.SOA:
+			warn("skipped SOA record; Porkbun manages the SOA record of its zones")
+			continue
+		case *dns.NS:
+			if name == domain {
+				warn("skipped NS record for the domain itself; set the domain's name servers with porkbun_domain_nameservers")
+				continue
+			}
+			record.Content = strings.TrimSuffix(v.Ns, ".")
+		case *dns.A:
+			record.Content = v.A.String()
+		case *dns.AAAA:
+			record.Content = v.AAAA.String()
+		case *dns.CNAME:
+			if aliasLines[line] {
+				record.Type = "ALIAS"
+			}
+			record.Content = strings.TrimSuffix(v.Target, ".")
+		case *dns.MX:
+			record.Prio = strconv.Itoa(int(v.Preference))
+			record.Content = strings.TrimSuffix(v.Mx, ".")
+		case *dns.TXT:
+			strs := make([]string, len(v.Txt))
+			for i, s := range v.Txt {
+				strs[i] = unescapeCharacterString(s)
+			}
+			record.Content = porkbun.EncodeTXTContent(strings.Join(strs, ""))
+		case *dns.SRV:
+			record.Prio = strconv.Itoa(int(v.Priority))
+			record.Content = fmt.Sprintf("%d %d %s", v.Weight, v.Port, strings.TrimSuffix(v.Target, "."))
+		case *dns.CAA:
+			record.Content = fmt.Sprintf("%d %s %s", v.Flag, v.Tag, porkbun.QuoteCharacterString(v.Value))
+		case *dns.TLSA:
+			record.Content = fmt.Sprintf("%d %d %d %s", v.Usage, v.Selector, v.MatchingType, v.Certificate)
+		case *dns.HTTPS:
+			record.Content = zoneSVCBContent(&v.SVCB)
+		case *dns.SVCB:
+			record.Content = zoneSVCBContent(v)
+		default:
+			warn("skipped %s record; Porkbun does not support %s records", recordType, recordType)
+			continue
+		}
+
+		duplicate := false
+		for _, other := range records {
+			if other.Name == record.Name && other.Type == record.Type && porkbun.SameContent(record.Type, other.Content, record.Content) {
+				duplicate = true
+				break
+			}
+		}
+		if duplicate {
+			warn("skipped duplicate %s record for %s", record.Type, name)
+			continue
+		}
+
+		records = append(records, record)
+	}
+
+	if err := zp.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return records, warnings, nil
+}
+
+// zoneRecordLine returns the line a record read from text starts on, where
+// text is the part of the zone file read for it and starts on line. A record
+// made by a $GENERATE directive is given the directive's line.
+func zoneRecordLine(text string, line int) int {
+	last := line
+	for i, l := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(l)
+		if trimmed == "" {
+			continue
+		}
+		if !strings.HasPrefix(trimmed, ";") && !strings.HasPrefix(trimmed, "$") {
+			return line + i
+		}
+		last = line + i
+	}
+	return last
+}
+
+// zoneSVCBContent returns the content of an SVCB or HTTPS record, with its
+// params sorted by key.
+func zoneSVCBContent(rr *dns.SVCB) string {
+	target := rr.Target
+	if target != "." {
+		target = strings.TrimSuffix(target, ".")
+	}
+
+	values := make([]dns.SVCBKeyValue, len(rr.Value))
+	copy(values, rr.Value)
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Key().String() < values[j].Key().String()
+	})
+
+	parts := []string{strconv.Itoa(int(rr.Priority)), target}
+	for _, kv := range values {
+		if value := kv.String(); value != "" {
+			parts = append(parts, kv.Key().String()+"="+value)
+		} else {
+			parts = append(parts, kv.Key().String())
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// unescapeCharacterString removes the escapes from a character-string as
+// written in a zone file: \DDD for a byte in decimal and a backslash before
+// any other character for that character.
+func unescapeCharacterString(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+3 < len(s) && isDigits(s[i+1:i+4]) {
+			n, _ := strconv.Atoi(s[i+1 : i+4])
+			b.WriteByte(byte(n))
+			i += 3
+			continue
+		}
+		i++
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,227 @@
+package zonefile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+func TestRender(t *testing.T) {
+	longValue := strings.Repeat("a", 300)
+
+	records := []porkbun.DNSRecord{
+		{ID: "1", Name: "www.example.com", Type: "CNAME", Content: "example.com", TTL: "600"},
+		{ID: "2", Name: "example.com", Type: "MX", Content: "mail2.example.com", TTL: "600", Prio: "20"},
+		{ID: "3", Name: "example.com", Type: "MX", Content: "mail.example.com", TTL: "600", Prio: "10"},
+		{ID: "4", Name: "example.com", Type: "A", Content: "192.0.2.1", TTL: "3600", Notes: "web\nserver"},
+		{ID: "5", Name: "_sip._tcp.example.com", Type: "SRV", Content: "5 5060 sip.example.com", TTL: "600", Prio: "10"},
+		{ID: "6", Name: "example.com", Type: "TXT", Content: `v=spf1 include:"quoted" -all`, TTL: "600"},
+		{ID: "7", Name: "dkim._domainkey.example.com", Type: "TXT", Content: longValue, TTL: "600"},
+		{ID: "8", Name: "example.com", Type: "CAA", Content: "0 issue letsencrypt.org", TTL: "600"},
+		{ID: "9", Name: "a.www.example.com", Type: "A", Content: "192.0.2.2", TTL: "600"},
+		{ID: "10", Name: "shop.example.com", Type: "ALIAS", Content: "lb.example.net", TTL: "600"},
+		{ID: "11", Name: "example.com", Type: "HTTPS", Content: "1 . alpn=h2", TTL: "600"},
+		{ID: "12", Name: "example.com", Type: "NS", Content: "curitiba.ns.porkbun.com", TTL: "86400"},
+	}
+
+	want := `; Zone file for example.com, exported from Porkbun
+$ORIGIN example.com.
+$TTL 600
+
+@	3600	IN	A	192.0.2.1 ; web server
+@	600	IN	CAA	0 issue "letsencrypt.org"
+@	600	IN	HTTPS	1 . alpn=h2
+@	600	IN	MX	10 mail.example.com.
+@	600	IN	MX	20 mail2.example.com.
+@	86400	IN	NS	curitiba.ns.porkbun.com.
+@	600	IN	TXT	"v=spf1 include:\"quoted\" -all"
+dkim._domainkey	600	IN	TXT	"` + longValue[:255] + `" "` + longValue[255:] + `"
+_sip._tcp	600	IN	SRV	10 5 5060 sip.example.com.
+; shop	600	IN	ALIAS	lb.example.net.
+www	600	IN	CNAME	example.com.
+a.www	600	IN	A	192.0.2.2
+`
+
+	got := Render("example.com", "600", records)
+	if got != want {
+		t.Fatalf("unexpected zone file:\n%s\nexpected:\n%s", got, want)
+	}
+
+	// The order of the records from the API does not matter
+	reversed := make([]porkbun.DNSRecord, len(records))
+	for i, record := range records {
+		reversed[len(records)-1-i] = record
+	}
+	if Render("example.com.", "600", reversed) != got {
+		t.Fatalf("expected the same zone file for records in another order")
+	}
+
+	// The zone file parses, with every record but the ALIAS one
+	parser := dns.NewZoneParser(strings.NewReader(got), "", "")
+	count := 0
+	for rr, ok := parser.Next(); ok; rr, ok = parser.Next() {
+		if txt, isTXT := rr.(*dns.TXT); isTXT && strings.HasPrefix(txt.Hdr.Name, "dkim.") {
+			if strings.Join(txt.Txt, "") != longValue {
+				t.Errorf("unexpected TXT value %q", txt.Txt)
+			}
+		}
+		count++
+	}
+	if err := parser.Err(); err != nil {
+		t.Fatalf("zone file does not parse: %s", err)
+	}
+	if count != len(records)-1 {
+		t.Fatalf("expected %d records, parsed %d", len(records)-1, count)
+	}
+}
+
+func TestCompareCanonicalNames(t *testing.T) {
+	// In canonical order
+	names := []string{"example.com", "a.example.com", "*.a.example.com", "b.a.example.com", "z.example.com"}
+
+	for i := 1; i < len(names); i++ {
+		if CompareCanonicalNames(names[i-1], names[i]) >= 0 {
+			t.Errorf("expected %q before %q", names[i-1], names[i])
+		}
+		if CompareCanonicalNames(names[i], names[i-1]) <= 0 {
+			t.Errorf("expected %q after %q", names[i], names[i-1])
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	zone := `$ORIGIN example.com.
+$TTL 3600
+@	IN	SOA	ns1.example.net. hostmaster.example.com. (
+		2024010101 ; serial
+		7200       ; refresh
+		3600 1209600 300 )
+@		IN	NS	ns1.example.net.
+@		IN	A	192.0.2.1 ; web server
+		IN	MX	10 mail
+www	600	IN	CNAME	@
+mail		IN	A	192.0.2.2
+_sip._tcp	IN	SRV	10 5 5060 sip.example.net.
+@		IN	TXT	( "v=spf1 include:\"quoted\""
+			  " -all" )
+@		IN	CAA	0 issue "letsencrypt.org"
+1.2.0.192.in-addr.arpa. IN PTR www
+shop		IN	ALIAS	lb.example.net.
+; blog	600	IN	ALIAS	lb.example.net. ; exported
+$ORIGIN sub.example.com.
+api		IN	AAAA	2001:db8::1
+other.example.org.	IN	A	192.0.2.3
+@		IN	TXT	"A \065 \\ b"
+`
+
+	records, warnings, err := Parse(zone, "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := []Record{
+		{porkbun.DNSRecord{Name: "example.com", Type: "A", Content: "192.0.2.1", TTL: "3600", Prio: "0", Notes: "web server"}, 8},
+		{porkbun.DNSRecord{Name: "example.com", Type: "MX", Content: "mail.example.com", TTL: "3600", Prio: "10"}, 9},
+		{porkbun.DNSRecord{Name: "www.example.com", Type: "CNAME", Content: "example.com", TTL: "600", Prio: "0"}, 10},
+		{porkbun.DNSRecord{Name: "mail.example.com", Type: "A", Content: "192.0.2.2", TTL: "3600", Prio: "0"}, 11},
+		{porkbun.DNSRecord{Name: "_sip._tcp.example.com", Type: "SRV", Content: "5 5060 sip.example.net", TTL: "3600", Prio: "10"}, 12},
+		{porkbun.DNSRecord{Name: "example.com", Type: "TXT", Content: `v=spf1 include:"quoted" -all`, TTL: "3600", Prio: "0"}, 13},
+		{porkbun.DNSRecord{Name: "example.com", Type: "CAA", Content: `0 issue "letsencrypt.org"`, TTL: "3600", Prio: "0"}, 15},
+		{porkbun.DNSRecord{Name: "shop.example.com", Type: "ALIAS", Content: "lb.example.net", TTL: "3600", Prio: "0"}, 17},
+		{porkbun.DNSRecord{Name: "blog.example.com", Type: "ALIAS", Content: "lb.example.net", TTL: "600", Prio: "0", Notes: "exported"}, 18},
+		{porkbun.DNSRecord{Name: "api.sub.example.com", Type: "AAAA", Content: "2001:db8::1", TTL: "3600", Prio: "0"}, 20},
+		{porkbun.DNSRecord{Name: "sub.example.com", Type: "TXT", Content: `A A \ b`, TTL: "3600", Prio: "0"}, 22},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("expected %d records, got %d: %+v", len(want), len(records), records)
+	}
+	for i := range want {
+		if records[i] != want[i] {
+			t.Errorf("record %d: expected %+v, got %+v", i, want[i], records[i])
+		}
+	}
+
+	wantWarnings := []string{"line 3: skipped SOA", "line 7: skipped NS", "line 16: skipped PTR", "line 21: skipped A"}
+	if len(warnings) != len(wantWarnings) {
+		t.Fatalf("expected %d warnings, got %q", len(wantWarnings), warnings)
+	}
+	for i, prefix := range wantWarnings {
+		if !strings.HasPrefix(warnings[i], prefix) {
+			t.Errorf("expected warning starting with %q, got %q", prefix, warnings[i])
+		}
+	}
+}
+
+func TestParse_RenderedZoneFile(t *testing.T) {
+	records := []porkbun.DNSRecord{
+		{Name: "example.com", Type: "A", Content: "192.0.2.1", TTL: "600", Prio: "0", Notes: "web server"},
+		{Name: "example.com", Type: "MX", Content: "mail.example.com", TTL: "600", Prio: "10"},
+		{Name: "dkim._domainkey.example.com", Type: "TXT", Content: porkbun.EncodeTXTContent(strings.Repeat("k", 300)), TTL: "600", Prio: "0"},
+		{Name: "_sip._tcp.example.com", Type: "SRV", Content: "5 5060 sip.example.com", TTL: "600", Prio: "10"},
+		{Name: "shop.example.com", Type: "ALIAS", Content: "lb.example.net", TTL: "600", Prio: "0"},
+		{Name: "example.com", Type: "HTTPS", Content: "1 . alpn=h2", TTL: "600", Prio: "0"},
+	}
+
+	parsed, warnings, err := Parse(Render("example.com", "600", records), "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("unexpected warnings: %q", warnings)
+	}
+	if len(parsed) != len(records) {
+		t.Fatalf("expected %d records, got %d: %+v", len(records), len(parsed), parsed)
+	}
+
+	for _, record := range records {
+		found := false
+		for _, p := range parsed {
+			if p.Name == record.Name && p.Type == record.Type && porkbun.SameContent(p.Type, p.Content, record.Content) &&
+				p.TTL == record.TTL && p.Prio == record.Prio && p.Notes == record.Notes {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("record %+v not parsed back, got %+v", record, parsed)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	_, _, err := Parse("@ IN A 192.0.2.1\nwww IN A not-an-address\n", "example.com")
+	if err == nil || !strings.Contains(err.Error(), "line: 2") {
+		t.Fatalf("expected an error on line 2, got %v", err)
+	}
+}
+
+func TestParse_StructuredContent(t *testing.T) {
+	zone := `_443._tcp	600	IN	TLSA	3 1 1 ( 0C72AC70B745AC19998811B131D662C9
+					AC69DBDBE7CB23E5B514B56664C5D3D6 )
+@	600	IN	HTTPS	1 lb.example.net. port=8443 alpn=h2,h3
+@	600	IN	CAA	128 iodef "mailto:security@example.com"
+`
+
+	records, warnings, err := Parse(zone, "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("unexpected warnings: %q", warnings)
+	}
+
+	want := []string{
+		"3 1 1 0C72AC70B745AC19998811B131D662C9AC69DBDBE7CB23E5B514B56664C5D3D6",
+		"1 lb.example.net alpn=h2,h3 port=8443",
+		`128 iodef "mailto:security@example.com"`,
+	}
+	if len(records) != len(want) {
+		t.Fatalf("expected %d records, got %d: %+v", len(want), len(records), records)
+	}
+	for i, content := range want {
+		if records[i].Content != content {
+			t.Errorf("record %d: expected content %q, got %q", i, content, records[i].Content)
+		}
+	}
+}
@@ -0,0 +1,451 @@
+// Package libdnsporkbun implements the libdns interfaces for Porkbun, so
+// that Caddy, certmagic and other libdns users manage records with the same
+// API client, and the same handling of the API's rate limits, as the
+// Terraform provider.
+package libdnsporkbun
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+// minTTL is porkbun.MinTTL as a duration. Lower TTLs are raised to it.
+const minTTL = porkbun.MinTTL * time.Second
+
+// Ensure Provider fully satisfies the libdns interfaces.
+var (
+	_ libdns.RecordGetter   = (*Provider)(nil)
+	_ libdns.RecordAppender = (*Provider)(nil)
+	_ libdns.RecordSetter   = (*Provider)(nil)
+	_ libdns.RecordDeleter  = (*Provider)(nil)
+	_ libdns.ZoneLister     = (*Provider)(nil)
+)
+
+// Provider manages the records of the domains in a Porkbun account.
+//
+// Records it returns are of the types defined by libdns where one exists,
+// with the Porkbun ID of the record as ProviderData. SetRecords and
+// DeleteRecords are not atomic: if they fail part way, the changes made
+// until then are kept.
+type Provider struct {
+	// APIKey and SecretAPIKey are the credentials used if Client is nil.
+	APIKey       string `json:"api_key,omitempty"`
+	SecretAPIKey string `json:"secret_api_key,omitempty"`
+
+	// Client is the client used to call the API. If nil, one is created
+	// with APIKey and SecretAPIKey on first use.
+	Client *porkbun.Client `json:"-"`
+
+	// mu serializes the changes to zones, which read the records of the
+	// zone before changing them.
+	mu sync.Mutex
+}
+
+// NewProvider returns a Provider that calls the API with client.
+func NewProvider(client *porkbun.Client) *Provider {
+	return &Provider{Client: client}
+}
+
+// client returns the client, creating it from the credentials if needed.
+// p.mu must be held.
+func (p *Provider) client() *porkbun.Client {
+	if p.Client == nil {
+		p.Client = porkbun.NewClient(p.APIKey, p.SecretAPIKey)
+	}
+	return p.Client
+}
+
+// GetRecords returns the records of zone.
+func (p *Provider) GetRecords(ctx context.Context, zone string) ([]libdns.Record, error) {
+	p.mu.Lock()
+	client := p.client()
+	p.mu.Unlock()
+
+	domain := zoneDomain(zone)
+	records, err := client.RetrieveDNSRecords(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]libdns.Record, len(records))
+	for i, record := range records {
+		result[i] = toLibdns(domain, record)
+	}
+	return result, nil
+}
+
+// AppendRecords creates records in zone, and returns them as created.
+func (p *Provider) AppendRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	domain := zoneDomain(zone)
+	live, err := p.client().RetrieveDNSRecords(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	var created []libdns.Record
+	for _, record := range records {
+		record, err := p.create(ctx, domain, record, &live)
+		if err != nil {
+			return created, err
+		}
+		created = append(created, record)
+	}
+	return created, nil
+}
+
+// SetRecords makes records the only records in zone with their names and
+// types. Records that already exist are kept, other records with the same
+// name and type are changed to the ones that are missing, and any left
+// over are deleted.
+func (p *Provider) SetRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	domain := zoneDomain(zone)
+	live, err := p.client().RetrieveDNSRecords(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	desired := make([]porkbun.DNSRecord, len(records))
+	for i, record := range records {
+		if desired[i], err = fromLibdns(domain, record); err != nil {
+			return nil, err
+		}
+	}
+
+	// Keep the live records that are already as desired, and collect the
+	// others with the names and types being set, which are reused or
+	// deleted
+	set := make([]libdns.Record, len(records))
+	done := make([]bool, len(desired))
+	var spare []porkbun.DNSRecord
+	for _, l := range live {
+		l.Name = strings.ToLower(strings.TrimSuffix(l.Name, "."))
+
+		inSet := false
+		for i, d := range desired {
+			if d.Name != l.Name || d.Type != l.Type {
+				continue
+			}
+			inSet = true
+			if !done[i] && sameRecord(d, l) {
+				done[i] = true
+				set[i] = toLibdns(domain, l)
+				break
+			}
+		}
+		if inSet && !containsRecord(set, l.ID) {
+			spare = append(spare, l)
+		}
+	}
+
+	for i, d := range desired {
+		if done[i] {
+			continue
+		}
+
+		reuse := -1
+		for j, s := range spare {
+			if s.Name == d.Name && s.Type == d.Type {
+				reuse = j
+				break
+			}
+		}
+
+		if reuse < 0 {
+			if set[i], err = p.create(ctx, domain, records[i], &live); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// The record keeps its notes, which libdns records do not have
+		d.ID, d.Notes = spare[reuse].ID, spare[reuse].Notes
+		spare = append(spare[:reuse], spare[reuse+1:]...)
+		err := p.client().EditDNSRecord(ctx, domain, d.ID, porkbun.EditDNSRecordRequest{
+			Name:    porkbun.Subdomain(d.Name, domain),
+			Type:    d.Type,
+			Content: d.Content,
+			TTL:     d.TTL,
+			Prio:    d.Prio,
+			Notes:   d.Notes,
+		})
+		if err != nil {
+			return nil, err
+		}
+		set[i] = toLibdns(domain, withDefaultTTL(d))
+	}
+
+	for _, s := range spare {
+		if err := p.client().DeleteDNSRecord(ctx, domain, s.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return set, nil
+}
+
+// DeleteRecords deletes the records of zone that match records, and returns
+// the ones deleted. The type, TTL and value of a record to delete may be
+// left empty to match any.
+func (p *Provider) DeleteRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	domain := zoneDomain(zone)
+	live, err := p.client().RetrieveDNSRecords(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []libdns.Record
+	for _, l := range live {
+		l.Name = strings.ToLower(strings.TrimSuffix(l.Name, "."))
+
+		for _, record := range records {
+			if !matchRecord(domain, record, l) {
+				continue
+			}
+			if err := p.client().DeleteDNSRecord(ctx, domain, l.ID); err != nil {
+				return deleted, err
+			}
+			deleted = append(deleted, toLibdns(domain, l))
+			break
+		}
+	}
+	return deleted, nil
+}
+
+// ListZones returns the domains in the account.
+func (p *Provider) ListZones(ctx context.Context) ([]libdns.Zone, error) {
+	p.mu.Lock()
+	client := p.client()
+	p.mu.Unlock()
+
+	domains, err := client.ListAllDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	zones := make([]libdns.Zone, len(domains))
+	for i, d := range domains {
+		zones[i] = libdns.Zone{Name: d.Domain + "."}
+	}
+	return zones, nil
+}
+
+// create creates record in domain, and returns it as created. live holds
+// the records of domain, and the new record is added to it.
+func (p *Provider) create(ctx context.Context, domain string, record libdns.Record, live *[]porkbun.DNSRecord) (libdns.Record, error) {
+	r, err := fromLibdns(domain, record)
+	if err != nil {
+		return nil, err
+	}
+
+	r.ID, err = p.client().CreateDNSRecordWithExisting(ctx, domain, porkbun.CreateDNSRecordRequest{
+		Name:    porkbun.Subdomain(r.Name, domain),
+		Type:    r.Type,
+		Content: r.Content,
+		TTL:     r.TTL,
+		Prio:    r.Prio,
+	}, *live)
+	if err != nil {
+		return nil, err
+	}
+	*live = append(*live, r)
+	return toLibdns(domain, withDefaultTTL(r)), nil
+}
+
+// fromLibdns returns record as a Porkbun record in domain, with a
+// fully-qualified name. Priorities of MX and SRV records are moved from the
+// content to the prio field, TXT values are split into character-strings
+// as needed, and host names lose their trailing dot.
+func fromLibdns(domain string, record libdns.Record) (porkbun.DNSRecord, error) {
+	rr := record.RR()
+	if parsed, err := rr.Parse(); err == nil {
+		record = parsed
+	}
+
+	r := porkbun.DNSRecord{
+		Name:    strings.ToLower(strings.TrimSuffix(libdns.AbsoluteName(rr.Name, domain), ".")),
+		Type:    rr.Type,
+		Content: rr.Data,
+	}
+	if rr.TTL > 0 {
+		r.TTL = strconv.Itoa(int(max(rr.TTL, minTTL) / time.Second))
+	}
+
+	switch rec := record.(type) {
+	case libdns.Address:
+		r.Content = rec.IP.String()
+	case libdns.CNAME:
+		r.Content = strings.TrimSuffix(rec.Target, ".")
+	case libdns.NS:
+		r.Content = strings.TrimSuffix(rec.Target, ".")
+	case libdns.MX:
+		r.Prio = strconv.Itoa(int(rec.Preference))
+		r.Content = strings.TrimSuffix(rec.Target, ".")
+	case libdns.SRV:
+		r.Prio = strconv.Itoa(int(rec.Priority))
+		r.Content = fmt.Sprintf("%d %d %s", rec.Weight, rec.Port, strings.TrimSuffix(rec.Target, "."))
+	case libdns.TXT:
+		r.Content = porkbun.EncodeTXTContent(rec.Text)
+	case libdns.CAA:
+		r.Content = fmt.Sprintf("%d %s %s", rec.Flags, rec.Tag, porkbun.QuoteCharacterString(rec.Value))
+	case libdns.ServiceBinding:
+		if rec.Target != "." {
+			rec.Target = strings.TrimSuffix(rec.Target, ".")
+		}
+		r.Content = strings.TrimSpace(fmt.Sprintf("%d %s %s", rec.Priority, rec.Target, rec.Params))
+	}
+
+	if r.Name != domain && !strings.HasSuffix(r.Name, "."+domain) {
+		return r, fmt.Errorf("%s record %s is not in zone %s", r.Type, r.Name, domain)
+	}
+	return r, nil
+}
+
+// toLibdns returns a Porkbun record of domain as a libdns record, of the type
+// libdns defines for it if there is one, with the record's ID as
+// ProviderData.
+func toLibdns(domain string, record porkbun.DNSRecord) libdns.Record {
+	seconds, _ := strconv.Atoi(record.TTL)
+	rr := libdns.RR{
+		Name: libdns.RelativeName(strings.ToLower(strings.TrimSuffix(record.Name, ".")), domain),
+		TTL:  time.Duration(seconds) * time.Second,
+		Type: record.Type,
+		Data: record.Content,
+	}
+
+	switch record.Type {
+	case "MX", "SRV":
+		rr.Data = porkbun.PrioOrZero(record.Prio) + " " + record.Content
+	case "TXT":
+		rr.Data = porkbun.DecodeTXTContent(record.Content)
+	}
+
+	parsed, err := rr.Parse()
+	if err != nil {
+		return rr
+	}
+
+	switch rec := parsed.(type) {
+	case libdns.Address:
+		rec.ProviderData = record.ID
+		return rec
+	case libdns.CNAME:
+		rec.ProviderData = record.ID
+		return rec
+	case libdns.NS:
+		rec.ProviderData = record.ID
+		return rec
+	case libdns.MX:
+		rec.ProviderData = record.ID
+		return rec
+	case libdns.SRV:
+		rec.ProviderData = record.ID
+		return rec
+	case libdns.TXT:
+		rec.ProviderData = record.ID
+		return rec
+	case libdns.CAA:
+		rec.ProviderData = record.ID
+		return rec
+	case libdns.ServiceBinding:
+		rec.ProviderData = record.ID
+		return rec
+	}
+	return parsed
+}
+
+// withDefaultTTL returns record with the TTL the API gives records created
+// or edited without one.
+func withDefaultTTL(record porkbun.DNSRecord) porkbun.DNSRecord {
+	if record.TTL == "" {
+		record.TTL = strconv.Itoa(int(minTTL / time.Second))
+	}
+	return record
+}
+
+// sameRecord reports whether the live record l is as desired by d.
+func sameRecord(d, l porkbun.DNSRecord) bool {
+	if !porkbun.SameContent(d.Type, l.Content, d.Content) || porkbun.PrioOrZero(d.Prio) != porkbun.PrioOrZero(l.Prio) {
+		return false
+	}
+	return d.TTL == "" || d.TTL == l.TTL
+}
+
+// matchRecord reports whether the live record l of domain matches record,
+// where an empty type, zero TTL or empty value match any. A TTL below the
+// minimum matches the minimum, which the record was created with.
+func matchRecord(domain string, record libdns.Record, l porkbun.DNSRecord) bool {
+	rr := record.RR()
+	name := strings.ToLower(strings.TrimSuffix(libdns.AbsoluteName(rr.Name, domain), "."))
+	if name != l.Name || (rr.Type != "" && rr.Type != l.Type) {
+		return false
+	}
+	if rr.TTL != 0 && strconv.Itoa(int(max(rr.TTL, minTTL)/time.Second)) != l.TTL {
+		return false
+	}
+	if rr.Data == "" {
+		return true
+	}
+
+	d, err := fromLibdns(domain, libdns.RR{Name: rr.Name, Type: l.Type, Data: rr.Data})
+	if err != nil {
+		return false
+	}
+	return porkbun.SameContent(l.Type, l.Content, d.Content) && (d.Prio == "" || porkbun.PrioOrZero(d.Prio) == porkbun.PrioOrZero(l.Prio))
+}
+
+// containsRecord reports whether records holds the record with ID id.
+func containsRecord(records []libdns.Record, id string) bool {
+	for _, record := range records {
+		if record != nil && providerID(record) == id {
+			return true
+		}
+	}
+	return false
+}
+
+// providerID returns the Porkbun ID in the ProviderData of record.
+func providerID(record libdns.Record) string {
+	var data any
+	switch rec := record.(type) {
+	case libdns.Address:
+		data = rec.ProviderData
+	case libdns.CNAME:
+		data = rec.ProviderData
+	case libdns.NS:
+		data = rec.ProviderData
+	case libdns.MX:
+		data = rec.ProviderData
+	case libdns.SRV:
+		data = rec.ProviderData
+	case libdns.TXT:
+		data = rec.ProviderData
+	case libdns.CAA:
+		data = rec.ProviderData
+	case libdns.ServiceBinding:
+		data = rec.ProviderData
+	}
+	id, _ := data.(string)
+	return id
+}
+
+// zoneDomain returns a libdns zone, such as "example.com.", as the domain
+// name the API takes.
+func zoneDomain(zone string) string {
+	return strings.ToLower(strings.TrimSuffix(zone, "."))
+}
@@ -0,0 +1,260 @@
+package libdnsporkbun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"net/netip"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/neenaoffline/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/neenaoffline/terraform-provider-porkbun/porkbun"
+)
+
+// newTestProvider returns a Provider calling api.
+func newTestProvider(t *testing.T, api *porkbuntest.API) *Provider {
+	t.Helper()
+
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	return NewProvider(porkbun.NewClient("pk1_test", "sk1_test", porkbun.WithBaseURL(server.URL)))
+}
+
+// rrs returns records as RRs, one per line, for comparing.
+func rrs(records []libdns.Record) []string {
+	var lines []string
+	for _, record := range records {
+		rr := record.RR()
+		lines = append(lines, fmt.Sprintf("%s %s %s %s", rr.Name, rr.TTL, rr.Type, rr.Data))
+	}
+	return lines
+}
+
+func expectLines(t *testing.T, what string, got, want []string) {
+	t.Helper()
+
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %s\n%s\ngot\n%s", what, strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestProvider_AppendRecords(t *testing.T) {
+	api := &porkbuntest.API{}
+	p := newTestProvider(t, api)
+	ctx := context.Background()
+
+	long := strings.Repeat("k", 300)
+	records := []libdns.Record{
+		libdns.Address{Name: "@", TTL: time.Hour, IP: netip.MustParseAddr("192.0.2.1")},
+		libdns.Address{Name: "www", IP: netip.MustParseAddr("2001:db8::1")},
+		libdns.CNAME{Name: "blog", TTL: time.Minute, Target: "www.example.com."},
+		libdns.MX{Name: "@", TTL: time.Hour, Preference: 10, Target: "mail.example.com."},
+		libdns.SRV{Service: "sip", Transport: "tcp", Name: "@", TTL: time.Hour, Priority: 10, Weight: 5, Port: 5060, Target: "sip.example.com."},
+		libdns.TXT{Name: "_acme-challenge", Text: `say "hi"`},
+		libdns.TXT{Name: "dkim._domainkey", Text: long},
+		libdns.CAA{Name: "@", Tag: "issue", Value: "letsencrypt.org"},
+		libdns.NS{Name: "sub", Target: "ns1.example.net."},
+		libdns.ServiceBinding{Scheme: "https", Name: "@", Priority: 1, Target: ".", Params: libdns.SvcParams{"alpn": {"h2"}}},
+		libdns.RR{Name: "_25._tcp.mail", Type: "TLSA", Data: "3 1 1 abcdef"},
+	}
+
+	created, err := p.AppendRecords(ctx, "example.com.", records)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectLines(t, "records", api.Summary(), []string{
+		"1 example.com A 3600 0 192.0.2.1",
+		"2 www.example.com AAAA 600 0 2001:db8::1",
+		"3 blog.example.com CNAME 600 0 www.example.com",
+		"4 example.com MX 3600 10 mail.example.com",
+		"5 _sip._tcp.example.com SRV 3600 10 5 5060 sip.example.com",
+		`6 _acme-challenge.example.com TXT 600 0 say "hi"`,
+		"7 dkim._domainkey.example.com TXT 600 0 " + porkbun.EncodeTXTContent(long),
+		`8 example.com CAA 600 0 0 issue "letsencrypt.org"`,
+		"9 sub.example.com NS 600 0 ns1.example.net",
+		"10 example.com HTTPS 600 0 1 . alpn=h2",
+		"11 _25._tcp.mail.example.com TLSA 600 0 3 1 1 abcdef",
+	})
+
+	// Records come back typed, with their IDs, and as they were given but
+	// for host names losing their trailing dot and TTLs raised to the
+	// minimum
+	want := []string{
+		"@ 1h0m0s A 192.0.2.1",
+		"www 10m0s AAAA 2001:db8::1",
+		"blog 10m0s CNAME www.example.com",
+		"@ 1h0m0s MX 10 mail.example.com",
+		"_sip._tcp 1h0m0s SRV 10 5 5060 sip.example.com",
+		`_acme-challenge 10m0s TXT say "hi"`,
+		"dkim._domainkey 10m0s TXT " + long,
+		`@ 10m0s CAA 0 issue "letsencrypt.org"`,
+		"sub 10m0s NS ns1.example.net",
+		"@ 10m0s HTTPS 1 . alpn=h2",
+		"_25._tcp.mail 10m0s TLSA 3 1 1 abcdef",
+	}
+	got, err := p.GetRecords(ctx, "example.com.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectLines(t, "records", rrs(got), want)
+
+	for i, typed := range []any{libdns.Address{}, libdns.Address{}, libdns.CNAME{}, libdns.MX{}, libdns.SRV{}, libdns.TXT{}, libdns.TXT{}, libdns.CAA{}, libdns.NS{}, libdns.ServiceBinding{}, libdns.RR{}} {
+		if fmt.Sprintf("%T", got[i]) != fmt.Sprintf("%T", typed) {
+			t.Errorf("expected record %d to be a %T, got %T", i, typed, got[i])
+		}
+		if _, ok := typed.(libdns.RR); !ok && providerID(got[i]) != fmt.Sprint(i+1) {
+			t.Errorf("expected record %d to have ID %d, got %q", i, i+1, providerID(got[i]))
+		}
+	}
+
+	// The records returned are the records as created
+	if created[3].(libdns.MX).ProviderData != "4" || created[3].(libdns.MX).Preference != 10 {
+		t.Errorf("unexpected created record %+v", created[3])
+	}
+	expectLines(t, "created records", rrs(created), want)
+
+	// Records outside the zone are refused
+	if _, err := p.AppendRecords(ctx, "example.com.", []libdns.Record{libdns.Address{Name: "www.example.net.", IP: netip.MustParseAddr("192.0.2.1")}}); err == nil {
+		t.Fatal("expected an error for a record outside the zone")
+	}
+}
+
+func TestProvider_SetRecords(t *testing.T) {
+	api := &porkbuntest.API{
+		NextID: 10,
+		Records: []porkbun.DNSRecord{
+			{ID: "1", Name: "example.com", Type: "A", Content: "192.0.2.1", TTL: "600", Prio: "0", Notes: "office router"},
+			{ID: "2", Name: "example.com", Type: "A", Content: "192.0.2.2", TTL: "600", Prio: "0"},
+			{ID: "3", Name: "example.com", Type: "A", Content: "192.0.2.3", TTL: "600", Prio: "0"},
+			{ID: "4", Name: "example.com", Type: "TXT", Content: "hello", TTL: "600", Prio: "0"},
+			{ID: "5", Name: "www.example.com", Type: "A", Content: "192.0.2.1", TTL: "600", Prio: "0"},
+		},
+	}
+	p := newTestProvider(t, api)
+	ctx := context.Background()
+
+	set, err := p.SetRecords(ctx, "example.com.", []libdns.Record{
+		libdns.Address{Name: "@", IP: netip.MustParseAddr("192.0.2.2")},
+		libdns.Address{Name: "@", TTL: time.Hour, IP: netip.MustParseAddr("192.0.2.4")},
+		libdns.Address{Name: "@", IP: netip.MustParseAddr("2001:db8::1")},
+		libdns.TXT{Name: "www", Text: "hello"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The A record already set is kept, another is changed to the new
+	// address and the last is deleted. The records of other names and
+	// types are left alone.
+	expectLines(t, "records", api.Summary(), []string{
+		"1 example.com A 3600 0 192.0.2.4",
+		"2 example.com A 600 0 192.0.2.2",
+		"4 example.com TXT 600 0 hello",
+		"5 www.example.com A 600 0 192.0.2.1",
+		"11 example.com AAAA 600 0 2001:db8::1",
+		"12 www.example.com TXT 600 0 hello",
+	})
+	if notes := api.Records[0].Notes; notes != "office router" {
+		t.Errorf("expected the changed record to keep its notes, got %q", notes)
+	}
+
+	expectLines(t, "set records", rrs(set), []string{
+		"@ 10m0s A 192.0.2.2",
+		"@ 1h0m0s A 192.0.2.4",
+		"@ 10m0s AAAA 2001:db8::1",
+		"www 10m0s TXT hello",
+	})
+	for i, id := range []string{"2", "1", "11", "12"} {
+		if got := providerID(set[i]); got != id {
+			t.Errorf("expected set record %d to have ID %s, got %q", i, id, got)
+		}
+	}
+
+	// Setting the records again changes nothing
+	before := api.Summary()
+	if _, err := p.SetRecords(ctx, "example.com.", []libdns.Record{
+		libdns.Address{Name: "@", IP: netip.MustParseAddr("192.0.2.4")},
+		libdns.Address{Name: "@", IP: netip.MustParseAddr("192.0.2.2")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	expectLines(t, "records", api.Summary(), before)
+}
+
+func TestProvider_DeleteRecords(t *testing.T) {
+	api := &porkbuntest.API{
+		Records: []porkbun.DNSRecord{
+			{ID: "1", Name: "example.com", Type: "A", Content: "192.0.2.1", TTL: "600", Prio: "0"},
+			{ID: "2", Name: "example.com", Type: "A", Content: "192.0.2.2", TTL: "3600", Prio: "0"},
+			{ID: "3", Name: "example.com", Type: "MX", Content: "mail.example.com", TTL: "600", Prio: "10"},
+			{ID: "4", Name: "example.com", Type: "MX", Content: "mail.example.com", TTL: "600", Prio: "20"},
+			{ID: "5", Name: "_acme-challenge.example.com", Type: "TXT", Content: "token1", TTL: "600", Prio: "0"},
+			{ID: "6", Name: "_acme-challenge.example.com", Type: "TXT", Content: "token2", TTL: "600", Prio: "0"},
+			{ID: "7", Name: "www.example.com", Type: "A", Content: "192.0.2.1", TTL: "600", Prio: "0"},
+			{ID: "8", Name: "www.example.com", Type: "AAAA", Content: "2001:db8::1", TTL: "600", Prio: "0"},
+			{ID: "9", Name: "_acme-challenge.www.example.com", Type: "TXT", Content: "token4", TTL: "600", Prio: "0"},
+		},
+	}
+	p := newTestProvider(t, api)
+	ctx := context.Background()
+
+	deleted, err := p.DeleteRecords(ctx, "example.com.", []libdns.Record{
+		// Exact match
+		libdns.TXT{Name: "_acme-challenge", Text: "token2"},
+		// Any value with a TTL
+		libdns.RR{Name: "@", Type: "A", TTL: time.Hour},
+		// Preference and target of an MX record
+		libdns.MX{Name: "@", Preference: 20, Target: "mail.example.com."},
+		// All records of a name
+		libdns.RR{Name: "www"},
+		// Nothing matches
+		libdns.TXT{Name: "_acme-challenge", Text: "token3"},
+		// A TTL below the minimum, which the record was created with
+		libdns.TXT{Name: "_acme-challenge.www", TTL: time.Minute, Text: "token4"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectLines(t, "records", api.Summary(), []string{
+		"1 example.com A 600 0 192.0.2.1",
+		"3 example.com MX 600 10 mail.example.com",
+		"5 _acme-challenge.example.com TXT 600 0 token1",
+	})
+	expectLines(t, "deleted records", rrs(deleted), []string{
+		"@ 1h0m0s A 192.0.2.2",
+		"@ 10m0s MX 20 mail.example.com",
+		"_acme-challenge 10m0s TXT token2",
+		"www 10m0s A 192.0.2.1",
+		"www 10m0s AAAA 2001:db8::1",
+		"_acme-challenge.www 10m0s TXT token4",
+	})
+}
+
+func TestProvider_ListZones(t *testing.T) {
+	p := newTestProvider(t, &porkbuntest.API{})
+
+	zones, err := p.ListZones(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zones) != 1 || zones[0].Name != "example.com." {
+		t.Fatalf("unexpected zones %+v", zones)
+	}
+}
+
+func TestProvider_Errors(t *testing.T) {
+	p := newTestProvider(t, &porkbuntest.API{})
+
+	_, err := p.GetRecords(context.Background(), "example.net.")
+	var apiErr *porkbun.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an API error, got %v", err)
+	}
+}
@@ -0,0 +1,299 @@
+// Package porkbun is a client for the Porkbun API (https://porkbun.com/api/json/v3/documentation).
+//
+// The client makes one request at a time, as the API asks, and retries
+// requests the API turns away with 503 Service Unavailable, which is how it
+// rate limits, with exponential backoff. A DNS record create whose outcome
+// is unknown, because the connection failed before a response came back, is
+// looked up in the zone before it is reported as failed, so that retrying
+// it does not create a duplicate.
+package porkbun
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultBaseURL is the base URL of the Porkbun API.
+	DefaultBaseURL = "https://api.porkbun.com/api/json/v3"
+
+	// recoverTimeout bounds the zone lookup made after an ambiguous create
+	recoverTimeout = 2 * time.Minute
+
+	// Retries of requests turned away with 503 Service Unavailable
+	maxRetries     = 5
+	baseRetryDelay = 2 * time.Second
+)
+
+// Client is the Porkbun API client
+type Client struct {
+	baseURL      string
+	apiKey       string
+	secretAPIKey string
+	httpClient   *http.Client
+	logger       *slog.Logger
+	limiter      Limiter
+	sem          chan struct{} // ensures only one API call at a time
+}
+
+// Limiter limits the rate of requests to the API. Wait blocks until a
+// request may be made, or returns an error if ctx is done first.
+// *rate.Limiter from golang.org/x/time/rate is a Limiter.
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests. The default has a
+// timeout of 30 seconds.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL sets the base URL of the API, such as the URL of a test
+// server. The default is DefaultBaseURL.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithLogger sets the logger the client writes retries and recovered
+// requests to. The context of the request is passed to the logger. By
+// default nothing is logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithLimiter sets a limiter that every request, including retries, waits
+// for before it is sent. By default requests are only limited to one at a
+// time.
+func WithLimiter(limiter Limiter) Option {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+// NewClient creates a new Porkbun API client
+func NewClient(apiKey, secretAPIKey string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:      DefaultBaseURL,
+		apiKey:       apiKey,
+		secretAPIKey: secretAPIKey,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		logger: slog.New(slog.DiscardHandler),
+		sem:    make(chan struct{}, 1),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// APIResponse is the base response from the API
+type APIResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// check returns an error for a response that is not successful, with
+// action describing the request.
+func (r APIResponse) check(action string) error {
+	if r.Status != "SUCCESS" {
+		return fmt.Errorf("failed to %s: %w", action, &APIError{StatusCode: http.StatusOK, Message: r.Message})
+	}
+	return nil
+}
+
+// APIError is returned when the API answers a request with an error.
+type APIError struct {
+	// StatusCode is the HTTP status of the response
+	StatusCode int
+	// Message is the message of the response, or its body if it has none
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode != http.StatusOK {
+		return fmt.Sprintf("API returned status %d: %s", e.StatusCode, e.Message)
+	}
+	return e.Message
+}
+
+// TransportError is returned when a request may have reached the API but no
+// usable response came back, so the outcome of the call is unknown.
+type TransportError struct {
+	Err error
+}
+
+func (e *TransportError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// response is a response from the API, which all embed APIResponse.
+type response interface {
+	check(action string) error
+}
+
+// call makes a request to endpoint with body, which may be nil, and the
+// API keys, and decodes the response into resp. It returns an error if the
+// response is not successful, with action describing the request.
+func (c *Client) call(ctx context.Context, endpoint string, body interface{}, resp response, action string) error {
+	reqBody, err := c.authenticate(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	respBody, err := c.doRequest(ctx, "POST", endpoint, reqBody)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(respBody, resp); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return resp.check(action)
+}
+
+// authenticate returns the fields of body, which must marshal to a JSON
+// object, with the API keys added.
+func (c *Client) authenticate(body interface{}) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, err
+		}
+	}
+
+	fields["apikey"], _ = json.Marshal(c.apiKey)
+	fields["secretapikey"], _ = json.Marshal(c.secretAPIKey)
+
+	return fields, nil
+}
+
+// doRequest performs an HTTP request to the Porkbun API
+// It ensures only one request is made at a time and retries on 503 errors
+// with exponential backoff. Waiting for a turn, the request itself and the
+// backoff delays all stop when ctx is done.
+func (c *Client) doRequest(ctx context.Context, method, endpoint string, body interface{}) ([]byte, error) {
+	select {
+	case c.sem <- struct{}{}:
+		defer func() { <-c.sem }()
+	case <-ctx.Done():
+		return nil, fmt.Errorf("gave up waiting for another API request to finish: %w", ctx.Err())
+	}
+
+	url := c.baseURL + endpoint
+
+	var jsonBody []byte
+	var err error
+	if body != nil {
+		jsonBody, err = json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+	}
+
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx); err != nil {
+				return nil, fmt.Errorf("gave up waiting for the request limiter: %w", err)
+			}
+		}
+
+		var reqBody io.Reader
+		if jsonBody != nil {
+			reqBody = bytes.NewBuffer(jsonBody)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, &TransportError{Err: fmt.Errorf("failed to execute request: %w", err)}
+		}
+
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, &TransportError{Err: fmt.Errorf("failed to read response body: %w", err)}
+		}
+
+		// Retry on 503 Service Unavailable (rate limiting)
+		if resp.StatusCode == http.StatusServiceUnavailable && attempt < maxRetries-1 {
+			delay := baseRetryDelay * time.Duration(1<<attempt) // exponential backoff
+
+			c.logger.DebugContext(ctx, "Rate limited by the Porkbun API, retrying",
+				"endpoint", endpoint,
+				"attempt", attempt+1,
+				"delay", delay.String(),
+			)
+
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return nil, fmt.Errorf("gave up waiting for API rate limit: %w", ctx.Err())
+			}
+			continue
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			message := string(respBody)
+			var apiResp APIResponse
+			if json.Unmarshal(respBody, &apiResp) == nil && apiResp.Message != "" {
+				message = apiResp.Message
+			}
+			return nil, &APIError{StatusCode: resp.StatusCode, Message: message}
+		}
+
+		return respBody, nil
+	}
+
+	return nil, fmt.Errorf("max retries exceeded")
+}
+
+// Ping tests the API connection and returns the IP address the request
+// came from.
+func (c *Client) Ping(ctx context.Context) (string, error) {
+	var resp PingResponse
+	if err := c.call(ctx, "/ping", nil, &resp, "ping the API"); err != nil {
+		return "", err
+	}
+	return resp.YourIP, nil
+}
+
+// PingResponse is the response from pinging the API
+type PingResponse struct {
+	APIResponse
+	YourIP string `json:"yourIp"`
+}
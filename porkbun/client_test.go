@@ -0,0 +1,209 @@
+package porkbun
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a Client with opts that talks to handler instead of
+// the Porkbun API.
+func newTestClient(t *testing.T, handler http.Handler, opts ...Option) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return NewClient("pk1_test", "sk1_test", append([]Option{WithBaseURL(server.URL)}, opts...)...)
+}
+
+// dropConnection closes the connection without writing a response, which
+// the client sees as a transport error.
+func dropConnection(t *testing.T, w http.ResponseWriter) {
+	t.Helper()
+
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		t.Fatalf("hijack: %s", err)
+	}
+	conn.Close()
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, v interface{}) {
+	t.Helper()
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		t.Fatalf("encode response: %s", err)
+	}
+}
+
+// decodeJSON decodes the body of a request as a JSON object.
+func decodeJSON(t *testing.T, r *http.Request) map[string]interface{} {
+	t.Helper()
+
+	var body map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		t.Fatalf("decode request: %s", err)
+	}
+	return body
+}
+
+func TestClient_SendsAPIKeysWithRequest(t *testing.T) {
+	var body map[string]interface{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/domain/updateNs/example.com", func(w http.ResponseWriter, r *http.Request) {
+		body = decodeJSON(t, r)
+		writeJSON(t, w, APIResponse{Status: "SUCCESS"})
+	})
+
+	client := newTestClient(t, mux)
+
+	if err := client.UpdateNameServers(context.Background(), "example.com", []string{"ns1.example.net"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if body["apikey"] != "pk1_test" || body["secretapikey"] != "sk1_test" {
+		t.Errorf("expected the API keys in the request, got %v", body)
+	}
+	if ns, _ := body["ns"].([]interface{}); len(ns) != 1 || ns[0] != "ns1.example.net" {
+		t.Errorf("expected the name servers in the request, got %v", body)
+	}
+}
+
+func TestClient_Ping(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, PingResponse{APIResponse: APIResponse{Status: "SUCCESS"}, YourIP: "192.0.2.1"})
+	})
+
+	client := newTestClient(t, mux)
+
+	ip, err := client.Ping(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if ip != "192.0.2.1" {
+		t.Fatalf("expected the IP address from the response, got %q", ip)
+	}
+}
+
+func TestClient_APIError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, APIResponse{Status: "ERROR", Message: "Invalid API key."})
+	})
+	mux.HandleFunc("/dns/retrieve/example.com", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		writeJSON(t, w, APIResponse{Status: "ERROR", Message: "Domain is not opted in to API access."})
+	})
+
+	client := newTestClient(t, mux)
+
+	_, err := client.Ping(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "Invalid API key." {
+		t.Fatalf("expected an API error with the response's message, got %v", err)
+	}
+	if err.Error() != "failed to ping the API: Invalid API key." {
+		t.Fatalf("unexpected error message %q", err)
+	}
+
+	_, err = client.RetrieveDNSRecords(context.Background(), "example.com")
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || apiErr.Message != "Domain is not opted in to API access." {
+		t.Fatalf("expected an API error with the status and message, got %v", err)
+	}
+}
+
+// roundTripperFunc is an http.RoundTripper calling a function.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestClient_WithHTTPClient(t *testing.T) {
+	var url string
+	httpClient := &http.Client{
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			url = r.URL.String()
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"status":"SUCCESS","yourIp":"192.0.2.1"}`)),
+			}, nil
+		}),
+	}
+
+	client := NewClient("pk1_test", "sk1_test", WithHTTPClient(httpClient))
+
+	if _, err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if url != DefaultBaseURL+"/ping" {
+		t.Fatalf("expected a request to %s/ping, got %s", DefaultBaseURL, url)
+	}
+}
+
+// countingLimiter is a Limiter that counts the requests it lets through,
+// and fails once it has let through limit of them.
+type countingLimiter struct {
+	count, limit int
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	if l.count == l.limit {
+		return errors.New("limit reached")
+	}
+	l.count++
+	return nil
+}
+
+func TestClient_WithLimiter(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, PingResponse{APIResponse: APIResponse{Status: "SUCCESS"}})
+	})
+
+	limiter := &countingLimiter{limit: 2}
+	client := newTestClient(t, mux, WithLimiter(limiter))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Ping(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if limiter.count != 2 {
+		t.Fatalf("expected every request to wait for the limiter, got %d waits", limiter.count)
+	}
+
+	_, err := client.Ping(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "limit reached") {
+		t.Fatalf("expected the limiter's error, got %v", err)
+	}
+}
+
+func TestClient_RateLimitWaitStopsAtDeadline(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/dns/retrieve/example.com", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	client := newTestClient(t, mux)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.RetrieveDNSRecords(ctx, "example.com")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline exceeded error, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected the rate limit backoff to stop at the deadline, took %s", elapsed)
+	}
+}
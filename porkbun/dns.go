@@ -0,0 +1,290 @@
+package porkbun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrNotFound is returned, wrapped, when a record looked up by ID does not
+// exist.
+var ErrNotFound = errors.New("not found")
+
+// MinTTL is the lowest TTL, in seconds, the API accepts. Records sent with a
+// lower TTL are stored with this one.
+const MinTTL = 600
+
+// DNSRecord represents a DNS record
+type DNSRecord struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Content string `json:"content"`
+	TTL     string `json:"ttl"`
+	Prio    string `json:"prio"`
+	Notes   string `json:"notes"`
+}
+
+// PrioOrZero returns prio, or "0" if the API left it empty.
+func PrioOrZero(prio string) string {
+	if prio == "" {
+		return "0"
+	}
+	return prio
+}
+
+// CreateDNSRecordRequest is the request to create a DNS record. Name is
+// the subdomain, or empty for the domain itself.
+type CreateDNSRecordRequest struct {
+	Name    string `json:"name,omitempty"`
+	Type    string `json:"type"`
+	Content string `json:"content"`
+	TTL     string `json:"ttl,omitempty"`
+	Prio    string `json:"prio,omitempty"`
+	Notes   string `json:"notes,omitempty"`
+}
+
+// CreateDNSRecordResponse is the response from creating a DNS record
+type CreateDNSRecordResponse struct {
+	APIResponse
+	ID int64 `json:"id"`
+}
+
+// EditDNSRecordRequest is the request to edit a DNS record
+type EditDNSRecordRequest struct {
+	Name    string `json:"name,omitempty"`
+	Type    string `json:"type"`
+	Content string `json:"content"`
+	TTL     string `json:"ttl,omitempty"`
+	Prio    string `json:"prio,omitempty"`
+	Notes   string `json:"notes,omitempty"`
+}
+
+// EditDNSRecordsByNameTypeRequest is the request to edit all DNS records
+// with a name and type
+type EditDNSRecordsByNameTypeRequest struct {
+	Content string `json:"content"`
+	TTL     string `json:"ttl,omitempty"`
+	Prio    string `json:"prio,omitempty"`
+	Notes   string `json:"notes,omitempty"`
+}
+
+// RetrieveDNSRecordsResponse is the response from retrieving DNS records
+type RetrieveDNSRecordsResponse struct {
+	APIResponse
+	Records []DNSRecord `json:"records"`
+}
+
+// CreateDNSRecord creates a new DNS record
+// If the request fails in a way that leaves its outcome unknown, the zone is
+// searched for a matching record that was not there before, so that a retry
+// does not create a duplicate. To know which records were there before, the
+// records with the name and type of the new one are looked up first; callers
+// that already have the records of the domain can pass them to
+// CreateDNSRecordWithExisting instead.
+func (c *Client) CreateDNSRecord(ctx context.Context, domain string, record CreateDNSRecordRequest) (string, error) {
+	existing, err := c.RetrieveDNSRecordsByNameType(ctx, domain, record.Type, record.Name)
+	if err != nil {
+		c.logger.WarnContext(ctx, "Unable to look up DNS records before create, an ambiguous failure will not be recovered",
+			"domain", domain,
+			"name", record.Name,
+			"type", record.Type,
+			"error", err.Error(),
+		)
+		return c.createDNSRecord(ctx, domain, record, nil, err)
+	}
+	return c.createDNSRecord(ctx, domain, record, existing, nil)
+}
+
+// CreateDNSRecordWithExisting is CreateDNSRecord for callers that have the
+// records of domain from before the create, such as from RetrieveDNSRecords,
+// which saves looking them up again. Only a record that is not in existing
+// is recovered after an ambiguous failure.
+func (c *Client) CreateDNSRecordWithExisting(ctx context.Context, domain string, record CreateDNSRecordRequest, existing []DNSRecord) (string, error) {
+	return c.createDNSRecord(ctx, domain, record, existing, nil)
+}
+
+// createDNSRecord creates a record, recovering it after an ambiguous failure
+// if it is not among existing. snapshotErr is the error looking up existing
+// failed with, in which case nothing is recovered.
+func (c *Client) createDNSRecord(ctx context.Context, domain string, record CreateDNSRecordRequest, existing []DNSRecord, snapshotErr error) (string, error) {
+	var resp CreateDNSRecordResponse
+	err := c.call(ctx, "/dns/create/"+domain, record, &resp, "create DNS record")
+	if err != nil {
+		var transportErr *TransportError
+		if !errors.As(err, &transportErr) {
+			return "", err
+		}
+		if snapshotErr != nil {
+			return "", fmt.Errorf("%w (unable to check for a created record: %s)", err, snapshotErr)
+		}
+
+		recoverCtx, cancel := recoverContext(ctx)
+		defer cancel()
+
+		records, listErr := c.RetrieveDNSRecords(recoverCtx, domain)
+		if listErr != nil {
+			return "", fmt.Errorf("%w (unable to check for a created record: %s)", err, listErr)
+		}
+
+		match := findMatchingRecord(newRecords(records, existing), domain, record)
+		if match == nil {
+			return "", err
+		}
+
+		c.logger.WarnContext(ctx, "Recovered DNS record after ambiguous create failure",
+			"domain", domain,
+			"id", match.ID,
+			"name", match.Name,
+			"type", match.Type,
+			"error", err.Error(),
+		)
+
+		return match.ID, nil
+	}
+
+	return strconv.FormatInt(resp.ID, 10), nil
+}
+
+// GetDNSRecord retrieves a specific DNS record by ID
+func (c *Client) GetDNSRecord(ctx context.Context, domain, recordID string) (*DNSRecord, error) {
+	var resp RetrieveDNSRecordsResponse
+	if err := c.call(ctx, "/dns/retrieve/"+domain+"/"+recordID, nil, &resp, "retrieve DNS record"); err != nil {
+		return nil, err
+	}
+
+	if len(resp.Records) == 0 {
+		return nil, fmt.Errorf("DNS record %w", ErrNotFound)
+	}
+
+	return &resp.Records[0], nil
+}
+
+// RetrieveDNSRecords retrieves all DNS records for a domain
+func (c *Client) RetrieveDNSRecords(ctx context.Context, domain string) ([]DNSRecord, error) {
+	var resp RetrieveDNSRecordsResponse
+	if err := c.call(ctx, "/dns/retrieve/"+domain, nil, &resp, "retrieve DNS records"); err != nil {
+		return nil, err
+	}
+
+	return resp.Records, nil
+}
+
+// RetrieveDNSRecordsByNameType retrieves the DNS records of a domain with a
+// type and subdomain, which is empty for the domain itself
+func (c *Client) RetrieveDNSRecordsByNameType(ctx context.Context, domain, recordType, subdomain string) ([]DNSRecord, error) {
+	var resp RetrieveDNSRecordsResponse
+	if err := c.call(ctx, nameTypeEndpoint("/dns/retrieveByNameType/", domain, recordType, subdomain), nil, &resp, "retrieve DNS records"); err != nil {
+		return nil, err
+	}
+
+	return resp.Records, nil
+}
+
+// recoverContext returns the context for the lookup made after an ambiguous
+// create. The deadline of ctx may have expired during the create itself, so
+// the lookup has its own, but it still stops if ctx is cancelled, such as
+// when Terraform is interrupted.
+func recoverContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	recoverCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), recoverTimeout)
+	stop := context.AfterFunc(ctx, func() {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			cancel()
+		}
+	})
+	return recoverCtx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// newRecords returns the records in records that are not in existing.
+func newRecords(records, existing []DNSRecord) []DNSRecord {
+	ids := make(map[string]bool, len(existing))
+	for _, record := range existing {
+		ids[record.ID] = true
+	}
+
+	var result []DNSRecord
+	for _, record := range records {
+		if !ids[record.ID] {
+			result = append(result, record)
+		}
+	}
+	return result
+}
+
+// findMatchingRecord returns the record in records that a create request
+// would have produced, or nil if there is none. The TTL is not compared, as
+// the API raises TTLs below its minimum. When several records match, the
+// newest one (highest ID) is returned.
+func findMatchingRecord(records []DNSRecord, domain string, req CreateDNSRecordRequest) *DNSRecord {
+	fqdn := domain
+	if req.Name != "" {
+		fqdn = req.Name + "." + domain
+	}
+
+	var match *DNSRecord
+	var matchID int64
+	for i := range records {
+		record := &records[i]
+		if !strings.EqualFold(record.Name, fqdn) || !strings.EqualFold(record.Type, req.Type) {
+			continue
+		}
+		if !SameContent(record.Type, record.Content, req.Content) {
+			continue
+		}
+		if req.Prio != "" && record.Prio != "" && record.Prio != req.Prio {
+			continue
+		}
+
+		id, err := strconv.ParseInt(record.ID, 10, 64)
+		if err != nil {
+			continue
+		}
+		if match == nil || id > matchID {
+			match = record
+			matchID = id
+		}
+	}
+
+	return match
+}
+
+// EditDNSRecord updates an existing DNS record
+func (c *Client) EditDNSRecord(ctx context.Context, domain, recordID string, record EditDNSRecordRequest) error {
+	var resp APIResponse
+	return c.call(ctx, "/dns/edit/"+domain+"/"+recordID, record, &resp, "edit DNS record")
+}
+
+// EditDNSRecordsByNameType updates all DNS records of a domain with a type
+// and subdomain, which is empty for the domain itself
+func (c *Client) EditDNSRecordsByNameType(ctx context.Context, domain, recordType, subdomain string, record EditDNSRecordsByNameTypeRequest) error {
+	var resp APIResponse
+	return c.call(ctx, nameTypeEndpoint("/dns/editByNameType/", domain, recordType, subdomain), record, &resp, "edit DNS records")
+}
+
+// DeleteDNSRecord deletes a DNS record
+func (c *Client) DeleteDNSRecord(ctx context.Context, domain, recordID string) error {
+	var resp APIResponse
+	return c.call(ctx, "/dns/delete/"+domain+"/"+recordID, nil, &resp, "delete DNS record")
+}
+
+// DeleteDNSRecordsByNameType deletes all DNS records of a domain with a type
+// and subdomain, which is empty for the domain itself
+func (c *Client) DeleteDNSRecordsByNameType(ctx context.Context, domain, recordType, subdomain string) error {
+	var resp APIResponse
+	return c.call(ctx, nameTypeEndpoint("/dns/deleteByNameType/", domain, recordType, subdomain), nil, &resp, "delete DNS records")
+}
+
+// nameTypeEndpoint returns the endpoint for the records of a domain with a
+// type and subdomain.
+func nameTypeEndpoint(prefix, domain, recordType, subdomain string) string {
+	endpoint := prefix + domain + "/" + recordType
+	if subdomain != "" {
+		endpoint += "/" + subdomain
+	}
+	return endpoint
+}
@@ -0,0 +1,318 @@
+package porkbun
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_CreateDNSRecord_RecoversAfterTransportError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/dns/create/example.com", func(w http.ResponseWriter, r *http.Request) {
+		dropConnection(t, w)
+	})
+	mux.HandleFunc("/dns/retrieveByNameType/example.com/A/www", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, RetrieveDNSRecordsResponse{
+			APIResponse: APIResponse{Status: "SUCCESS"},
+			Records: []DNSRecord{
+				{ID: "100", Name: "www.example.com", Type: "A", Content: "192.0.2.9", TTL: "600", Prio: "0"},
+			},
+		})
+	})
+	mux.HandleFunc("/dns/retrieve/example.com", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, RetrieveDNSRecordsResponse{
+			APIResponse: APIResponse{Status: "SUCCESS"},
+			Records: []DNSRecord{
+				{ID: "100", Name: "www.example.com", Type: "A", Content: "192.0.2.9", TTL: "600", Prio: "0"},
+				{ID: "101", Name: "www.example.com", Type: "A", Content: "192.0.2.1", TTL: "600", Prio: "0"},
+				{ID: "102", Name: "example.com", Type: "A", Content: "192.0.2.1", TTL: "600", Prio: "0"},
+			},
+		})
+	})
+
+	var logs bytes.Buffer
+	client := newTestClient(t, mux, WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+
+	id, err := client.CreateDNSRecord(context.Background(), "example.com", CreateDNSRecordRequest{
+		Name:    "www",
+		Type:    "A",
+		Content: "192.0.2.1",
+		TTL:     "600",
+	})
+	if err != nil {
+		t.Fatalf("expected the created record to be recovered, got error: %s", err)
+	}
+	if id != "101" {
+		t.Fatalf("expected recovered record ID 101, got %s", id)
+	}
+	if !strings.Contains(logs.String(), "Recovered DNS record") {
+		t.Fatalf("expected the recovery to be logged, got %q", logs.String())
+	}
+}
+
+func TestClient_CreateDNSRecord_TransportErrorWithoutMatch(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/dns/create/example.com", func(w http.ResponseWriter, r *http.Request) {
+		dropConnection(t, w)
+	})
+	mux.HandleFunc("/dns/retrieve/example.com", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, RetrieveDNSRecordsResponse{
+			APIResponse: APIResponse{Status: "SUCCESS"},
+			Records: []DNSRecord{
+				{ID: "100", Name: "www.example.com", Type: "A", Content: "192.0.2.9", TTL: "600", Prio: "0"},
+			},
+		})
+	})
+
+	client := newTestClient(t, mux)
+
+	_, err := client.CreateDNSRecord(context.Background(), "example.com", CreateDNSRecordRequest{
+		Name:    "www",
+		Type:    "A",
+		Content: "192.0.2.1",
+	})
+	if err == nil {
+		t.Fatal("expected an error when no matching record exists")
+	}
+	if !strings.Contains(err.Error(), "failed to execute request") {
+		t.Fatalf("expected the original transport error, got: %s", err)
+	}
+}
+
+func TestClient_CreateDNSRecord_DoesNotRecoverOtherRecords(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/dns/create/example.com", func(w http.ResponseWriter, r *http.Request) {
+		dropConnection(t, w)
+	})
+	mux.HandleFunc("/dns/retrieveByNameType/example.com/A/www", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, RetrieveDNSRecordsResponse{
+			APIResponse: APIResponse{Status: "SUCCESS"},
+			Records: []DNSRecord{
+				{ID: "100", Name: "www.example.com", Type: "A", Content: "192.0.2.1", TTL: "600", Prio: "0"},
+			},
+		})
+	})
+	mux.HandleFunc("/dns/retrieve/example.com", func(w http.ResponseWriter, r *http.Request) {
+		// An identical record that was there before the create
+		writeJSON(t, w, RetrieveDNSRecordsResponse{
+			APIResponse: APIResponse{Status: "SUCCESS"},
+			Records: []DNSRecord{
+				{ID: "100", Name: "www.example.com", Type: "A", Content: "192.0.2.1", TTL: "600", Prio: "0"},
+			},
+		})
+	})
+
+	client := newTestClient(t, mux)
+
+	_, err := client.CreateDNSRecord(context.Background(), "example.com", CreateDNSRecordRequest{
+		Name:    "www",
+		Type:    "A",
+		Content: "192.0.2.1",
+		TTL:     "600",
+	})
+	if err == nil {
+		t.Fatal("expected an error when only records the create did not make match")
+	}
+}
+
+func TestClient_CreateDNSRecord_RecoversRaisedTTL(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/dns/create/example.com", func(w http.ResponseWriter, r *http.Request) {
+		dropConnection(t, w)
+	})
+	mux.HandleFunc("/dns/retrieveByNameType/example.com/A/www", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, RetrieveDNSRecordsResponse{APIResponse: APIResponse{Status: "SUCCESS"}})
+	})
+	mux.HandleFunc("/dns/retrieve/example.com", func(w http.ResponseWriter, r *http.Request) {
+		// The API stores its minimum TTL for a lower one
+		writeJSON(t, w, RetrieveDNSRecordsResponse{
+			APIResponse: APIResponse{Status: "SUCCESS"},
+			Records: []DNSRecord{
+				{ID: "101", Name: "www.example.com", Type: "A", Content: "192.0.2.1", TTL: "600", Prio: "0"},
+			},
+		})
+	})
+
+	client := newTestClient(t, mux)
+
+	id, err := client.CreateDNSRecord(context.Background(), "example.com", CreateDNSRecordRequest{
+		Name:    "www",
+		Type:    "A",
+		Content: "192.0.2.1",
+		TTL:     "300",
+	})
+	if err != nil {
+		t.Fatalf("expected the created record to be recovered, got error: %s", err)
+	}
+	if id != "101" {
+		t.Fatalf("expected recovered record ID 101, got %s", id)
+	}
+}
+
+func TestClient_CreateDNSRecordWithExisting(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/dns/create/example.com", func(w http.ResponseWriter, r *http.Request) {
+		dropConnection(t, w)
+	})
+	mux.HandleFunc("/dns/retrieveByNameType/", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("expected no lookup before the create when the existing records are given")
+	})
+	mux.HandleFunc("/dns/retrieve/example.com", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, RetrieveDNSRecordsResponse{
+			APIResponse: APIResponse{Status: "SUCCESS"},
+			Records: []DNSRecord{
+				{ID: "100", Name: "www.example.com", Type: "A", Content: "192.0.2.1", TTL: "600", Prio: "0"},
+				{ID: "101", Name: "www.example.com", Type: "A", Content: "192.0.2.1", TTL: "600", Prio: "0"},
+			},
+		})
+	})
+
+	client := newTestClient(t, mux)
+
+	existing := []DNSRecord{{ID: "100", Name: "www.example.com", Type: "A", Content: "192.0.2.1", TTL: "600", Prio: "0"}}
+	id, err := client.CreateDNSRecordWithExisting(context.Background(), "example.com", CreateDNSRecordRequest{
+		Name:    "www",
+		Type:    "A",
+		Content: "192.0.2.1",
+	}, existing)
+	if err != nil {
+		t.Fatalf("expected the created record to be recovered, got error: %s", err)
+	}
+	if id != "101" {
+		t.Fatalf("expected recovered record ID 101, got %s", id)
+	}
+}
+
+func TestClient_CreateDNSRecord_LogsFailedLookup(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/dns/create/example.com", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, CreateDNSRecordResponse{APIResponse: APIResponse{Status: "SUCCESS"}, ID: 101})
+	})
+	mux.HandleFunc("/dns/retrieveByNameType/example.com/A/www", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, APIResponse{Status: "ERROR", Message: "Lookup failed."})
+	})
+
+	var logs bytes.Buffer
+	client := newTestClient(t, mux, WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+
+	id, err := client.CreateDNSRecord(context.Background(), "example.com", CreateDNSRecordRequest{Name: "www", Type: "A", Content: "192.0.2.1"})
+	if err != nil || id != "101" {
+		t.Fatalf("expected the record to be created, got %q, %v", id, err)
+	}
+	if !strings.Contains(logs.String(), "Lookup failed.") {
+		t.Fatalf("expected the failed lookup to be logged, got %q", logs.String())
+	}
+}
+
+func TestClient_CreateDNSRecord_RecoveryStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	release := make(chan struct{})
+	defer close(release)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/dns/retrieveByNameType/example.com/A/www", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, RetrieveDNSRecordsResponse{APIResponse: APIResponse{Status: "SUCCESS"}})
+	})
+	mux.HandleFunc("/dns/create/example.com", func(w http.ResponseWriter, r *http.Request) {
+		dropConnection(t, w)
+	})
+	mux.HandleFunc("/dns/retrieve/example.com", func(w http.ResponseWriter, r *http.Request) {
+		// The lookup hangs until the caller gives up
+		cancel()
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	})
+
+	client := newTestClient(t, mux)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.CreateDNSRecord(ctx, "example.com", CreateDNSRecordRequest{Name: "www", Type: "A", Content: "192.0.2.1"})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected an error when the lookup is cancelled")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected the lookup to stop when the context is cancelled")
+	}
+}
+
+func TestRecoverContext_OutlivesDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+
+	recoverCtx, recoverCancel := recoverContext(ctx)
+	defer recoverCancel()
+	if recoverCtx.Err() != nil {
+		t.Fatalf("expected the lookup to outlive an expired deadline, got %s", recoverCtx.Err())
+	}
+}
+
+func TestClient_GetDNSRecord_NotFound(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/dns/retrieve/example.com/123", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, RetrieveDNSRecordsResponse{APIResponse: APIResponse{Status: "SUCCESS"}})
+	})
+
+	client := newTestClient(t, mux)
+
+	_, err := client.GetDNSRecord(context.Background(), "example.com", "123")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestClient_DNSRecordsByNameType(t *testing.T) {
+	var paths []string
+	var content interface{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/dns/", func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if strings.HasPrefix(r.URL.Path, "/dns/editByNameType/") {
+			content = decodeJSON(t, r)["content"]
+		}
+		writeJSON(t, w, RetrieveDNSRecordsResponse{
+			APIResponse: APIResponse{Status: "SUCCESS"},
+			Records:     []DNSRecord{{ID: "1", Name: "www.example.com", Type: "A", Content: "192.0.2.1"}},
+		})
+	})
+
+	client := newTestClient(t, mux)
+	ctx := context.Background()
+
+	records, err := client.RetrieveDNSRecordsByNameType(ctx, "example.com", "A", "www")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(records) != 1 || records[0].ID != "1" {
+		t.Fatalf("unexpected records %+v", records)
+	}
+	if err := client.EditDNSRecordsByNameType(ctx, "example.com", "A", "", EditDNSRecordsByNameTypeRequest{Content: "192.0.2.2"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := client.DeleteDNSRecordsByNameType(ctx, "example.com", "TXT", "_acme-challenge"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := "/dns/retrieveByNameType/example.com/A/www,/dns/editByNameType/example.com/A,/dns/deleteByNameType/example.com/TXT/_acme-challenge"
+	if got := strings.Join(paths, ","); got != want {
+		t.Fatalf("expected requests to %s, got %s", want, got)
+	}
+	if content != "192.0.2.2" {
+		t.Fatalf("expected the content in the edit request, got %v", content)
+	}
+}
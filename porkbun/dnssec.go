@@ -0,0 +1,59 @@
+package porkbun
+
+import (
+	"context"
+	"sort"
+)
+
+// DNSSECRecord is a DS record at the registry. Records created with key
+// data rather than a digest set the KeyData fields.
+type DNSSECRecord struct {
+	KeyTag          string `json:"keyTag"`
+	Alg             string `json:"alg"`
+	DigestType      string `json:"digestType"`
+	Digest          string `json:"digest"`
+	MaxSigLife      string `json:"maxSigLife,omitempty"`
+	KeyDataFlags    string `json:"keyDataFlags,omitempty"`
+	KeyDataProtocol string `json:"keyDataProtocol,omitempty"`
+	KeyDataAlgo     string `json:"keyDataAlgo,omitempty"`
+	KeyDataPubKey   string `json:"keyDataPubKey,omitempty"`
+}
+
+// GetDNSSECRecordsResponse is the response from retrieving DNSSEC records,
+// keyed by key tag
+type GetDNSSECRecordsResponse struct {
+	APIResponse
+	Records map[string]DNSSECRecord `json:"records"`
+}
+
+// CreateDNSSECRecord creates a DS record for a domain at the registry
+func (c *Client) CreateDNSSECRecord(ctx context.Context, domain string, record DNSSECRecord) error {
+	var resp APIResponse
+	return c.call(ctx, "/dnssec/createRecord/"+domain, record, &resp, "create DNSSEC record")
+}
+
+// GetDNSSECRecords retrieves the DS records of a domain at the registry,
+// ordered by key tag
+func (c *Client) GetDNSSECRecords(ctx context.Context, domain string) ([]DNSSECRecord, error) {
+	var resp GetDNSSECRecordsResponse
+	if err := c.call(ctx, "/dnssec/getRecords/"+domain, nil, &resp, "get DNSSEC records"); err != nil {
+		return nil, err
+	}
+
+	records := make([]DNSSECRecord, 0, len(resp.Records))
+	for _, record := range resp.Records {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].KeyTag < records[j].KeyTag
+	})
+
+	return records, nil
+}
+
+// DeleteDNSSECRecord deletes the DS record with a key tag from a domain at
+// the registry
+func (c *Client) DeleteDNSSECRecord(ctx context.Context, domain, keyTag string) error {
+	var resp APIResponse
+	return c.call(ctx, "/dnssec/deleteRecord/"+domain+"/"+keyTag, nil, &resp, "delete DNSSEC record")
+}
@@ -0,0 +1,27 @@
+package porkbun
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestClient_GetDNSSECRecords(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/dnssec/getRecords/example.com", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"SUCCESS","records":{`+
+			`"64087":{"keyTag":"64087","alg":"13","digestType":"2","digest":"15E445BD"},`+
+			`"12345":{"keyTag":"12345","alg":"8","digestType":"2","digest":"AB12CD34"}}}`)
+	})
+
+	client := newTestClient(t, mux)
+
+	records, err := client.GetDNSSECRecords(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(records) != 2 || records[0].KeyTag != "12345" || records[1].Digest != "15E445BD" {
+		t.Fatalf("expected the records ordered by key tag, got %+v", records)
+	}
+}
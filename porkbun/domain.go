@@ -0,0 +1,230 @@
+package porkbun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// listAllDomainsPageSize is the number of domains /domain/listAll returns per page
+const listAllDomainsPageSize = 1000
+
+// UpdateNameServersRequest is the request to update domain name servers
+type UpdateNameServersRequest struct {
+	NS []string `json:"ns"`
+}
+
+// GetNameServersResponse is the response from retrieving name servers
+type GetNameServersResponse struct {
+	APIResponse
+	NS []string `json:"ns"`
+}
+
+// UpdateNameServers updates the name servers for a domain
+func (c *Client) UpdateNameServers(ctx context.Context, domain string, nameservers []string) error {
+	var resp APIResponse
+	return c.call(ctx, "/domain/updateNs/"+domain, UpdateNameServersRequest{NS: nameservers}, &resp, "update name servers")
+}
+
+// GetNameServers retrieves the name servers for a domain
+func (c *Client) GetNameServers(ctx context.Context, domain string) ([]string, error) {
+	var resp GetNameServersResponse
+	if err := c.call(ctx, "/domain/getNs/"+domain, nil, &resp, "get name servers"); err != nil {
+		return nil, err
+	}
+
+	return resp.NS, nil
+}
+
+// Domain represents a domain in the Porkbun account
+type Domain struct {
+	Domain     string `json:"domain"`
+	Status     string `json:"status"`
+	TLD        string `json:"tld"`
+	CreateDate string `json:"createDate"`
+	ExpireDate string `json:"expireDate"`
+}
+
+// ListAllDomainsRequest is the request to list the domains in the account
+type ListAllDomainsRequest struct {
+	Start string `json:"start"`
+}
+
+// ListAllDomainsResponse is the response from listing the domains in the account
+type ListAllDomainsResponse struct {
+	APIResponse
+	Domains []Domain `json:"domains"`
+}
+
+// ListAllDomains retrieves every domain in the account, following pagination
+func (c *Client) ListAllDomains(ctx context.Context) ([]Domain, error) {
+	var domains []Domain
+
+	for {
+		req := ListAllDomainsRequest{
+			Start: strconv.Itoa(len(domains)),
+		}
+
+		var resp ListAllDomainsResponse
+		if err := c.call(ctx, "/domain/listAll", req, &resp, "list domains"); err != nil {
+			return nil, err
+		}
+
+		domains = append(domains, resp.Domains...)
+
+		if len(resp.Domains) < listAllDomainsPageSize {
+			return domains, nil
+		}
+	}
+}
+
+// DomainAvailability is the result of checking whether a domain can be
+// registered
+type DomainAvailability struct {
+	Avail          string `json:"avail"`
+	Type           string `json:"type"`
+	Price          string `json:"price"`
+	FirstYearPromo string `json:"firstYearPromo"`
+	RegularPrice   string `json:"regularPrice"`
+	Premium        string `json:"premium"`
+}
+
+// Available reports whether the domain can be registered.
+func (a DomainAvailability) Available() bool {
+	return a.Avail == "yes"
+}
+
+// CheckDomainResponse is the response from checking a domain's availability
+type CheckDomainResponse struct {
+	APIResponse
+	Response DomainAvailability `json:"response"`
+}
+
+// CheckDomain checks whether a domain can be registered, and at what price
+func (c *Client) CheckDomain(ctx context.Context, domain string) (*DomainAvailability, error) {
+	var resp CheckDomainResponse
+	if err := c.call(ctx, "/domain/checkDomain/"+domain, nil, &resp, "check domain"); err != nil {
+		return nil, err
+	}
+
+	return &resp.Response, nil
+}
+
+// URLForward is a URL forward of a domain. Type is "temporary" or
+// "permanent"; IncludePath and Wildcard are "yes" or "no".
+type URLForward struct {
+	ID          string `json:"id,omitempty"`
+	Subdomain   string `json:"subdomain"`
+	Location    string `json:"location"`
+	Type        string `json:"type"`
+	IncludePath string `json:"includePath"`
+	Wildcard    string `json:"wildcard"`
+}
+
+// GetURLForwardingResponse is the response from retrieving URL forwards
+type GetURLForwardingResponse struct {
+	APIResponse
+	Forwards []URLForward `json:"forwards"`
+}
+
+// AddURLForward adds a URL forward to a domain. The ID of forward is
+// ignored.
+func (c *Client) AddURLForward(ctx context.Context, domain string, forward URLForward) error {
+	forward.ID = ""
+
+	var resp APIResponse
+	return c.call(ctx, "/domain/addUrlForward/"+domain, forward, &resp, "add URL forward")
+}
+
+// GetURLForwarding retrieves the URL forwards of a domain
+func (c *Client) GetURLForwarding(ctx context.Context, domain string) ([]URLForward, error) {
+	var resp GetURLForwardingResponse
+	if err := c.call(ctx, "/domain/getUrlForwarding/"+domain, nil, &resp, "get URL forwarding"); err != nil {
+		return nil, err
+	}
+
+	return resp.Forwards, nil
+}
+
+// DeleteURLForward deletes a URL forward of a domain
+func (c *Client) DeleteURLForward(ctx context.Context, domain, forwardID string) error {
+	var resp APIResponse
+	return c.call(ctx, "/domain/deleteUrlForward/"+domain+"/"+forwardID, nil, &resp, "delete URL forward")
+}
+
+// GlueRecordRequest is the request to create or update a glue record
+type GlueRecordRequest struct {
+	IPs []string `json:"ips"`
+}
+
+// GlueRecord is a glue record of a domain: a name server host within the
+// domain and its addresses.
+type GlueRecord struct {
+	Host string
+	IPv4 []string
+	IPv6 []string
+}
+
+// UnmarshalJSON reads a glue record as the API writes it, as a pair of the
+// host and its addresses.
+func (g *GlueRecord) UnmarshalJSON(data []byte) error {
+	var pair []json.RawMessage
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return fmt.Errorf("expected a host and its addresses, got %s", data)
+	}
+
+	var addresses struct {
+		V4 []string `json:"v4"`
+		V6 []string `json:"v6"`
+	}
+	if err := json.Unmarshal(pair[0], &g.Host); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(pair[1], &addresses); err != nil {
+		return err
+	}
+	g.IPv4, g.IPv6 = addresses.V4, addresses.V6
+
+	return nil
+}
+
+// GetGlueRecordsResponse is the response from retrieving glue records
+type GetGlueRecordsResponse struct {
+	APIResponse
+	Hosts []GlueRecord `json:"hosts"`
+}
+
+// CreateGlueRecord creates a glue record for the host subdomain of a
+// domain, such as ns1, with its IPv4 and IPv6 addresses
+func (c *Client) CreateGlueRecord(ctx context.Context, domain, subdomain string, ips []string) error {
+	var resp APIResponse
+	return c.call(ctx, "/domain/createGlue/"+domain+"/"+subdomain, GlueRecordRequest{IPs: ips}, &resp, "create glue record")
+}
+
+// UpdateGlueRecord replaces the addresses of the glue record for the host
+// subdomain of a domain
+func (c *Client) UpdateGlueRecord(ctx context.Context, domain, subdomain string, ips []string) error {
+	var resp APIResponse
+	return c.call(ctx, "/domain/updateGlue/"+domain+"/"+subdomain, GlueRecordRequest{IPs: ips}, &resp, "update glue record")
+}
+
+// DeleteGlueRecord deletes the glue record for the host subdomain of a
+// domain
+func (c *Client) DeleteGlueRecord(ctx context.Context, domain, subdomain string) error {
+	var resp APIResponse
+	return c.call(ctx, "/domain/deleteGlue/"+domain+"/"+subdomain, nil, &resp, "delete glue record")
+}
+
+// GetGlueRecords retrieves the glue records of a domain
+func (c *Client) GetGlueRecords(ctx context.Context, domain string) ([]GlueRecord, error) {
+	var resp GetGlueRecordsResponse
+	if err := c.call(ctx, "/domain/getGlue/"+domain, nil, &resp, "get glue records"); err != nil {
+		return nil, err
+	}
+
+	return resp.Hosts, nil
+}
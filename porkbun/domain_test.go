@@ -0,0 +1,131 @@
+package porkbun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_ListAllDomains_FollowsPagination(t *testing.T) {
+	var starts []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/domain/listAll", func(w http.ResponseWriter, r *http.Request) {
+		var req ListAllDomainsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %s", err)
+		}
+		starts = append(starts, req.Start)
+
+		count := listAllDomainsPageSize
+		if req.Start != "0" {
+			count = 2
+		}
+
+		resp := ListAllDomainsResponse{APIResponse: APIResponse{Status: "SUCCESS"}}
+		for i := 0; i < count; i++ {
+			resp.Domains = append(resp.Domains, Domain{Domain: fmt.Sprintf("example%s-%d.com", req.Start, i)})
+		}
+		writeJSON(t, w, resp)
+	})
+
+	client := newTestClient(t, mux)
+
+	domains, err := client.ListAllDomains(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(domains) != listAllDomainsPageSize+2 {
+		t.Fatalf("expected %d domains, got %d", listAllDomainsPageSize+2, len(domains))
+	}
+	if strings.Join(starts, ",") != "0,1000" {
+		t.Fatalf("expected pages starting at 0 and 1000, got %v", starts)
+	}
+}
+
+func TestClient_URLForwarding(t *testing.T) {
+	var added map[string]interface{}
+	var deleted string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/domain/addUrlForward/example.com", func(w http.ResponseWriter, r *http.Request) {
+		added = decodeJSON(t, r)
+		writeJSON(t, w, APIResponse{Status: "SUCCESS"})
+	})
+	mux.HandleFunc("/domain/getUrlForwarding/example.com", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"SUCCESS","forwards":[{"id":"22049209","subdomain":"","location":"https://example.net","type":"temporary","includePath":"no","wildcard":"yes"}]}`)
+	})
+	mux.HandleFunc("/domain/deleteUrlForward/example.com/", func(w http.ResponseWriter, r *http.Request) {
+		deleted = strings.TrimPrefix(r.URL.Path, "/domain/deleteUrlForward/example.com/")
+		writeJSON(t, w, APIResponse{Status: "SUCCESS"})
+	})
+
+	client := newTestClient(t, mux)
+	ctx := context.Background()
+
+	err := client.AddURLForward(ctx, "example.com", URLForward{
+		ID:          "ignored",
+		Location:    "https://example.net",
+		Type:        "permanent",
+		IncludePath: "no",
+		Wildcard:    "no",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok := added["id"]; ok || added["location"] != "https://example.net" || added["type"] != "permanent" {
+		t.Fatalf("unexpected request %v", added)
+	}
+
+	forwards, err := client.GetURLForwarding(ctx, "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(forwards) != 1 || forwards[0].ID != "22049209" || forwards[0].Wildcard != "yes" {
+		t.Fatalf("unexpected forwards %+v", forwards)
+	}
+
+	if err := client.DeleteURLForward(ctx, "example.com", forwards[0].ID); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if deleted != "22049209" {
+		t.Fatalf("expected forward 22049209 to be deleted, got %q", deleted)
+	}
+}
+
+func TestClient_GetGlueRecords(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/domain/getGlue/example.com", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"SUCCESS","hosts":[["ns1.example.com",{"v6":["2001:db8::1"],"v4":["192.0.2.1","192.0.2.2"]}]]}`)
+	})
+
+	client := newTestClient(t, mux)
+
+	hosts, err := client.GetGlueRecords(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(hosts) != 1 || hosts[0].Host != "ns1.example.com" || len(hosts[0].IPv4) != 2 || hosts[0].IPv6[0] != "2001:db8::1" {
+		t.Fatalf("unexpected glue records %+v", hosts)
+	}
+}
+
+func TestClient_CheckDomain(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/domain/checkDomain/example.com", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"SUCCESS","response":{"avail":"yes","type":"registration","price":"9.68","firstYearPromo":"no","regularPrice":"9.68","premium":"no"}}`)
+	})
+
+	client := newTestClient(t, mux)
+
+	availability, err := client.CheckDomain(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !availability.Available() || availability.Price != "9.68" {
+		t.Fatalf("unexpected availability %+v", availability)
+	}
+}
@@ -0,0 +1,46 @@
+package porkbun
+
+import (
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// Subdomain returns the name of a record relative to domain, as the API
+// takes it when creating and editing records. Names within domain, with or
+// without a trailing dot and in any case, have the domain stripped, and
+// domain itself becomes the empty string. Other names are returned without
+// a trailing dot but otherwise as they are.
+func Subdomain(name, domain string) string {
+	name = strings.TrimSuffix(name, ".")
+	domain = strings.TrimSuffix(domain, ".")
+
+	if strings.EqualFold(name, domain) {
+		return ""
+	}
+	if n := len(name) - len(domain); n > 1 && name[n-1] == '.' && strings.EqualFold(name[n:], domain) {
+		return name[:n-1]
+	}
+	return name
+}
+
+// IDNToUnicode converts the punycode labels of a domain name, such as the
+// names the API returns, back to Unicode. Labels that cannot be converted
+// are left as they are.
+func IDNToUnicode(name string) string {
+	if !strings.Contains(strings.ToLower(name), "xn--") {
+		return name
+	}
+
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		if !strings.HasPrefix(strings.ToLower(label), "xn--") {
+			continue
+		}
+		if unicode, err := idna.Lookup.ToUnicode(label); err == nil {
+			labels[i] = unicode
+		}
+	}
+
+	return strings.Join(labels, ".")
+}
@@ -0,0 +1,48 @@
+package porkbun
+
+import "testing"
+
+func TestSubdomain(t *testing.T) {
+	cases := []struct {
+		name, domain, want string
+	}{
+		{name: "www", domain: "example.com", want: "www"},
+		{name: "www.example.com", domain: "example.com", want: "www"},
+		{name: "www.example.com.", domain: "example.com", want: "www"},
+		{name: "WWW.Example.COM", domain: "example.com", want: "WWW"},
+		{name: "example.com", domain: "example.com", want: ""},
+		{name: "example.com.", domain: "example.com", want: ""},
+		{name: "", domain: "example.com", want: ""},
+		{name: "www.myexample.com", domain: "example.com", want: "www.myexample.com"},
+		{name: "www.other.org.", domain: "example.com", want: "www.other.org"},
+	}
+
+	for _, tc := range cases {
+		if got := Subdomain(tc.name, tc.domain); got != tc.want {
+			t.Errorf("%q in %q: expected %q, got %q", tc.name, tc.domain, tc.want, got)
+		}
+	}
+}
+
+func TestIDNToUnicode(t *testing.T) {
+	cases := map[string]string{
+		"_dmarc.xn--mller-kva.de": "_dmarc.müller.de",
+		"www.example.com":         "www.example.com",
+		"xn--99.example.com":      "xn--99.example.com",
+	}
+
+	for name, want := range cases {
+		if got := IDNToUnicode(name); got != want {
+			t.Errorf("%q: expected %q, got %q", name, want, got)
+		}
+	}
+}
+
+func TestPrioOrZero(t *testing.T) {
+	if got := PrioOrZero(""); got != "0" {
+		t.Errorf("expected 0, got %q", got)
+	}
+	if got := PrioOrZero("10"); got != "10" {
+		t.Errorf("expected 10, got %q", got)
+	}
+}
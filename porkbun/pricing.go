@@ -0,0 +1,28 @@
+package porkbun
+
+import "context"
+
+// TLDPricing is the price of registering, renewing and transferring a
+// domain under a TLD, in US dollars
+type TLDPricing struct {
+	Registration string `json:"registration"`
+	Renewal      string `json:"renewal"`
+	Transfer     string `json:"transfer"`
+}
+
+// GetPricingResponse is the response from retrieving pricing, keyed by TLD
+type GetPricingResponse struct {
+	APIResponse
+	Pricing map[string]TLDPricing `json:"pricing"`
+}
+
+// GetPricing retrieves the default pricing of every TLD Porkbun supports,
+// keyed by TLD without a leading dot
+func (c *Client) GetPricing(ctx context.Context) (map[string]TLDPricing, error) {
+	var resp GetPricingResponse
+	if err := c.call(ctx, "/pricing/get", nil, &resp, "get pricing"); err != nil {
+		return nil, err
+	}
+
+	return resp.Pricing, nil
+}
@@ -0,0 +1,25 @@
+package porkbun
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestClient_GetPricing(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pricing/get", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"SUCCESS","pricing":{"com":{"registration":"9.68","renewal":"9.68","transfer":"9.68"}}}`)
+	})
+
+	client := newTestClient(t, mux)
+
+	pricing, err := client.GetPricing(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if pricing["com"].Renewal != "9.68" {
+		t.Fatalf("unexpected pricing %+v", pricing)
+	}
+}
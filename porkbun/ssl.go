@@ -0,0 +1,26 @@
+package porkbun
+
+import "context"
+
+// SSLBundle is the certificate Porkbun issues for a domain, in PEM format
+type SSLBundle struct {
+	CertificateChain string `json:"certificatechain"`
+	PrivateKey       string `json:"privatekey"`
+	PublicKey        string `json:"publickey"`
+}
+
+// RetrieveSSLBundleResponse is the response from retrieving an SSL bundle
+type RetrieveSSLBundleResponse struct {
+	APIResponse
+	SSLBundle
+}
+
+// RetrieveSSLBundle retrieves the SSL certificate bundle of a domain
+func (c *Client) RetrieveSSLBundle(ctx context.Context, domain string) (*SSLBundle, error) {
+	var resp RetrieveSSLBundleResponse
+	if err := c.call(ctx, "/ssl/retrieve/"+domain, nil, &resp, "retrieve SSL bundle"); err != nil {
+		return nil, err
+	}
+
+	return &resp.SSLBundle, nil
+}
@@ -0,0 +1,25 @@
+package porkbun
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestClient_RetrieveSSLBundle(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ssl/retrieve/example.com", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"SUCCESS","certificatechain":"CHAIN","privatekey":"PRIVATE","publickey":"PUBLIC"}`)
+	})
+
+	client := newTestClient(t, mux)
+
+	bundle, err := client.RetrieveSSLBundle(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if bundle.CertificateChain != "CHAIN" || bundle.PrivateKey != "PRIVATE" || bundle.PublicKey != "PUBLIC" {
+		t.Fatalf("unexpected bundle %+v", bundle)
+	}
+}
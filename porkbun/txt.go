@@ -0,0 +1,122 @@
+package porkbun
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxTXTStringLength is the longest a single character-string in a TXT
+// record can be, per RFC 1035 section 3.3.
+const MaxTXTStringLength = 255
+
+// EncodeTXTContent returns the content to send to the API for a TXT record
+// value. Values that fit in one character-string, or that are already
+// written as quoted strings, are sent as they are. Longer values, such as
+// DKIM keys, are split into quoted strings of at most 255 bytes each.
+func EncodeTXTContent(value string) string {
+	if len(value) <= MaxTXTStringLength {
+		return value
+	}
+	if _, ok := ParseTXTStrings(value); ok {
+		return value
+	}
+
+	return QuoteTXTStrings(SplitTXTValue(value))
+}
+
+// DecodeTXTContent returns the value of TXT record content, joining quoted
+// character-strings back together. Content that is not written as quoted
+// strings is returned as it is.
+func DecodeTXTContent(content string) string {
+	if strs, ok := ParseTXTStrings(content); ok {
+		return strings.Join(strs, "")
+	}
+	return content
+}
+
+// SameContent reports whether two record contents are equivalent. TXT
+// content is compared exactly, however it is split into quoted strings;
+// other types hold hostnames or addresses and are compared
+// case-insensitively, ignoring a trailing dot.
+func SameContent(recordType, a, b string) bool {
+	if strings.EqualFold(recordType, "TXT") {
+		return DecodeTXTContent(a) == DecodeTXTContent(b)
+	}
+	return strings.EqualFold(strings.TrimSuffix(a, "."), strings.TrimSuffix(b, "."))
+}
+
+// SplitTXTValue splits value into chunks of at most 255 bytes, without
+// splitting a UTF-8 encoded character across chunks.
+func SplitTXTValue(value string) []string {
+	var chunks []string
+
+	for len(value) > MaxTXTStringLength {
+		n := MaxTXTStringLength
+		for n > 0 && !utf8.RuneStart(value[n]) {
+			n--
+		}
+		chunks = append(chunks, value[:n])
+		value = value[n:]
+	}
+
+	return append(chunks, value)
+}
+
+// QuoteTXTStrings writes character-strings in zone file format: each one in
+// double quotes, with quotes and backslashes escaped, separated by spaces.
+func QuoteTXTStrings(strs []string) string {
+	quoted := make([]string, len(strs))
+	for i, s := range strs {
+		quoted[i] = QuoteCharacterString(s)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// QuoteCharacterString writes s in double quotes, escaping quotes and
+// backslashes.
+func QuoteCharacterString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// ParseTXTStrings parses content written as one or more quoted
+// character-strings separated by whitespace, as produced by
+// QuoteTXTStrings. It reports false if content is not in that form.
+func ParseTXTStrings(content string) ([]string, bool) {
+	var strs []string
+
+	rest := strings.TrimSpace(content)
+	if rest == "" {
+		return nil, false
+	}
+
+	for rest != "" {
+		if rest[0] != '"' {
+			return nil, false
+		}
+
+		var b strings.Builder
+		i := 1
+		for ; i < len(rest) && rest[i] != '"'; i++ {
+			if rest[i] == '\\' && i+1 < len(rest) {
+				i++
+			}
+			b.WriteByte(rest[i])
+		}
+		if i == len(rest) {
+			// Unterminated quoted string
+			return nil, false
+		}
+		strs = append(strs, b.String())
+
+		// Quoted strings must be separated by whitespace
+		next := strings.TrimLeft(rest[i+1:], " \t")
+		if next != "" && len(next) == len(rest[i+1:]) {
+			return nil, false
+		}
+		rest = next
+	}
+
+	return strs, true
+}
@@ -0,0 +1,89 @@
+package porkbun
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncodeTXTContent(t *testing.T) {
+	short := "v=spf1 include:_spf.porkbun.com -all"
+	if got := EncodeTXTContent(short); got != short {
+		t.Fatalf("expected short values to be sent as they are, got %q", got)
+	}
+
+	quoted := `"` + strings.Repeat("a", 255) + `" "b"`
+	if got := EncodeTXTContent(quoted); got != quoted {
+		t.Fatalf("expected quoted values to be sent as they are, got %q", got)
+	}
+
+	dkim := "v=DKIM1; k=rsa; p=" + strings.Repeat("MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8A", 12) + `"quoted\`
+	encoded := EncodeTXTContent(dkim)
+
+	strs, ok := ParseTXTStrings(encoded)
+	if !ok {
+		t.Fatalf("expected quoted strings, got %q", encoded)
+	}
+	if len(strs) != 2 {
+		t.Fatalf("expected 2 strings, got %d: %q", len(strs), encoded)
+	}
+	for _, s := range strs {
+		if len(s) > MaxTXTStringLength {
+			t.Fatalf("expected strings of at most %d bytes, got %d", MaxTXTStringLength, len(s))
+		}
+	}
+	if got := DecodeTXTContent(encoded); got != dkim {
+		t.Fatalf("expected the value to round trip, got %q", got)
+	}
+}
+
+func TestSplitTXTValue_KeepsCharactersWhole(t *testing.T) {
+	value := strings.Repeat("a", 254) + "é" + "b"
+
+	chunks := SplitTXTValue(value)
+	if len(chunks) != 2 || chunks[0] != strings.Repeat("a", 254) || chunks[1] != "éb" {
+		t.Fatalf("expected the split before the two-byte character, got %q", chunks)
+	}
+}
+
+func TestParseTXTStrings(t *testing.T) {
+	cases := []struct {
+		content string
+		want    []string
+		wantOK  bool
+	}{
+		{content: `"abc"`, want: []string{"abc"}, wantOK: true},
+		{content: `"abc" "def"`, want: []string{"abc", "def"}, wantOK: true},
+		{content: `"a\"b" "c\\d"`, want: []string{`a"b`, `c\d`}, wantOK: true},
+		{content: `abc`},
+		{content: `"abc" def`},
+		{content: `"abc""def"`},
+		{content: `"abc`},
+		{content: ``},
+	}
+
+	for _, tc := range cases {
+		got, ok := ParseTXTStrings(tc.content)
+		if ok != tc.wantOK || strings.Join(got, "|") != strings.Join(tc.want, "|") {
+			t.Errorf("%q: expected (%q, %t), got (%q, %t)", tc.content, tc.want, tc.wantOK, got, ok)
+		}
+	}
+}
+
+func TestSameContent(t *testing.T) {
+	cases := []struct {
+		recordType string
+		a, b       string
+		want       bool
+	}{
+		{recordType: "CNAME", a: "Example.com.", b: "example.com", want: true},
+		{recordType: "A", a: "192.0.2.1", b: "192.0.2.2"},
+		{recordType: "TXT", a: `"abc" "def"`, b: "abcdef", want: true},
+		{recordType: "TXT", a: "ABC", b: "abc"},
+	}
+
+	for _, tc := range cases {
+		if got := SameContent(tc.recordType, tc.a, tc.b); got != tc.want {
+			t.Errorf("%s %q, %q: expected %t, got %t", tc.recordType, tc.a, tc.b, tc.want, got)
+		}
+	}
+}